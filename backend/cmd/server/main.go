@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,10 +11,125 @@ import (
 	"time"
 
 	"opsnotebook/backend/internal/api"
+	"opsnotebook/backend/internal/auth"
 	"opsnotebook/backend/internal/config"
+	"opsnotebook/backend/internal/logging"
 	"opsnotebook/backend/internal/target"
 )
 
+// buildController assembles the traffic controller chain: the built-in
+// prod-mutating guardrail and tracing header injection always run first, an
+// audit log is added when configured, and any traffic_rules from
+// config.json run last. Because Chain.InterceptExec/InterceptProxy stop at
+// the first deny, traffic_rules can only add restrictions (deny, require a
+// confirmation token, force auditing) on top of the built-in guardrail --
+// there's no "allow" action, so a rule can't override DenyProdMutatingController
+// once it's denied a call.
+func buildController(cfg *config.Config) (target.TrafficController, error) {
+	chain := target.Chain{
+		target.DenyProdMutatingController{},
+		target.HeaderInjectionController{},
+	}
+	var auditor *target.AuditController
+	if cfg.TrafficAuditLog != "" {
+		var err error
+		auditor, err = target.NewAuditController(cfg.TrafficAuditLog)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, auditor)
+	}
+	if len(cfg.TrafficRules) > 0 {
+		chain = append(chain, target.NewRuleController(cfg.TrafficRules, auditor))
+	}
+	return chain, nil
+}
+
+// buildLogger assembles the logging.Logger Manager logs connect/proxy/exec
+// activity through. An empty Logging list defaults to a single console
+// sink, matching the log.Printf-to-stdout behavior this replaces; multiple
+// entries are fanned out via a MultiSink so e.g. console and JSON-to-file
+// can run side by side.
+func buildLogger(entries []config.LoggingConfig) (logging.Logger, error) {
+	if len(entries) == 0 {
+		return logging.ConsoleSink{}, nil
+	}
+
+	sinks := make(logging.MultiSink, 0, len(entries))
+	for _, e := range entries {
+		switch e.Type {
+		case "console", "":
+			sinks = append(sinks, logging.ConsoleSink{})
+		case "file":
+			if e.Path == "" {
+				return nil, fmt.Errorf("logging entry has type \"file\" but no path")
+			}
+			sink, err := logging.NewFileSink(e.Path, e.MaxSizeMB, e.MaxBackups, time.Duration(e.MaxAgeHours)*time.Hour)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "json":
+			sink := logging.NewJSONSink()
+			if e.Path != "" {
+				f, err := os.OpenFile(e.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					return nil, fmt.Errorf("logging entry type \"json\": %w", err)
+				}
+				sink.Writer = f
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown logging entry type %q", e.Type)
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+// buildDiscoverer constructs the target.Discoverer cfg selects, or nil if
+// cfg is nil. Only "file" is wired up here; Consul/Kubernetes discovery
+// need a live client, which main() doesn't otherwise construct.
+func buildDiscoverer(cfg *config.DiscoveryConfig) (target.Discoverer, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	switch cfg.Type {
+	case "file":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("discovery.type is \"file\" but discovery.path is not configured")
+		}
+		return target.FileDiscoverer{Path: cfg.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown discovery.type %q", cfg.Type)
+	}
+}
+
+// buildAuthenticator constructs the configured auth.Authenticator, or nil
+// (no authentication) when cfg is nil or its type is "none"/empty.
+func buildAuthenticator(cfg *config.AuthConfig) (auth.Authenticator, error) {
+	if cfg == nil || cfg.Type == "" || cfg.Type == "none" {
+		return nil, nil
+	}
+
+	switch cfg.Type {
+	case "basic":
+		if cfg.Basic == nil {
+			return nil, fmt.Errorf("auth.type is \"basic\" but auth.basic is not configured")
+		}
+		return auth.NewBasicAuthenticator(cfg.Basic.HtpasswdFile)
+	case "oidc":
+		if cfg.OIDC == nil {
+			return nil, fmt.Errorf("auth.type is \"oidc\" but auth.oidc is not configured")
+		}
+		return auth.NewOIDCAuthenticator(context.Background(), cfg.OIDC.DiscoveryURL, cfg.OIDC.ClientID, cfg.OIDC.UserClaim, cfg.OIDC.GroupsClaim)
+	default:
+		return nil, fmt.Errorf("unknown auth.type %q", cfg.Type)
+	}
+}
+
 func main() {
 	// 1. Load Config
 	configPath := os.Getenv("CONFIG_PATH")
@@ -33,13 +149,47 @@ func main() {
 	}
 
 	// 2. Initialize Target Manager
-	manager := target.NewManager(cfg)
+	logger, err := buildLogger(cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	manager := target.NewManager(cfg, logger)
 
 	// Start connections
 	go manager.ConnectAll()
 
+	discoveryCtx, cancelDiscovery := context.WithCancel(context.Background())
+	if discoverer, err := buildDiscoverer(cfg.Discovery); err != nil {
+		log.Fatalf("Failed to configure discovery: %v", err)
+	} else if discoverer != nil {
+		go manager.Run(discoveryCtx, discoverer)
+	}
+
 	// 3. Initialize API Server
 	apiServer := api.NewServer(manager)
+	if authenticator, err := buildAuthenticator(cfg.Auth); err != nil {
+		log.Fatalf("Failed to configure auth: %v", err)
+	} else {
+		apiServer.Auth = authenticator
+	}
+
+	controller, err := buildController(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure traffic controller: %v", err)
+	}
+	apiServer.Controller = controller
+	apiServer.MetricsAuth = cfg.MetricsAuth
+
+	reload := func() error {
+		newCfg, err := config.LoadWithPattern(configPath, targetPattern)
+		if err != nil {
+			return err
+		}
+		manager.Reload(newCfg)
+		return nil
+	}
+	apiServer.Reload = reload
+
 	mux := apiServer.Routes()
 
 	// 4. Setup Static File Server
@@ -50,6 +200,35 @@ func main() {
 	fs := http.FileServer(http.Dir(staticDir))
 	mux.Handle("/", fs)
 
+	// SIGHUP triggers the same reload as POST /api/reload.
+	go func() {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		for range hupCh {
+			log.Println("Received SIGHUP, reloading config...")
+			if err := reload(); err != nil {
+				log.Printf("Config reload failed: %v", err)
+			}
+		}
+	}()
+
+	// Optionally watch the config file and reload automatically on edits.
+	if os.Getenv("CONFIG_WATCH") == "1" {
+		stopWatch, err := config.Watch(configPath, func(*config.Config) {
+			// Re-run through reload() rather than the Config Watch already
+			// loaded, so the TARGET_PATTERN filter keeps being applied.
+			log.Println("Detected config change, reloading...")
+			if err := reload(); err != nil {
+				log.Printf("Config reload failed: %v", err)
+			}
+		})
+		if err != nil {
+			log.Printf("Config watch disabled: %v", err)
+		} else {
+			defer stopWatch()
+		}
+	}
+
 	// 5. Start HTTP Server
 	server := &http.Server{Addr: ":12808", Handler: mux}
 
@@ -59,6 +238,7 @@ func main() {
 		<-sigCh
 
 		log.Println("Shutting down...")
+		cancelDiscovery()
 		manager.Shutdown()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -10,6 +12,10 @@ import (
 
 	"fmt"
 
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"opsnotebook/backend/internal/auth"
 	"opsnotebook/backend/internal/config"
 	"opsnotebook/backend/internal/target"
 )
@@ -47,6 +53,22 @@ func colorizeMethod(method string) string {
 
 type Server struct {
 	Manager *target.Manager
+	Auth    auth.Authenticator // nil disables authentication entirely
+
+	// Reload re-reads the on-disk config and reconciles it into Manager. Set
+	// by main to a closure over the configured path/pattern; nil disables
+	// the /api/reload endpoint (returns 501).
+	Reload func() error
+
+	// Controller intercepts exec/proxy calls before dispatch and observes
+	// their outcome afterward. Nil skips interception entirely.
+	Controller target.TrafficController
+
+	// MetricsAuth puts /metrics behind withAuth like every other endpoint.
+	// Defaults to false: most Prometheus setups don't send this server's
+	// auth credentials on scrape requests, so requiring them by default
+	// would silently break metrics collection on upgrade.
+	MetricsAuth bool
 }
 
 func NewServer(m *target.Manager) *Server {
@@ -55,15 +77,105 @@ func NewServer(m *target.Manager) *Server {
 
 func (s *Server) Routes() *http.ServeMux {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/config", s.withLogging(s.handleConfig))
-	mux.HandleFunc("/api/targets", s.withLogging(s.handleTargets))
+	mux.HandleFunc("/api/config", s.withAuth(s.withLogging(s.handleConfig)))
+	mux.HandleFunc("/api/targets", s.withAuth(s.withLogging(s.handleTargets)))
 	// Note: handleTargetAction has its own logging (proxy/exec/reconnect/status log individually)
-	mux.HandleFunc("/api/targets/", s.handleTargetAction)
-	mux.HandleFunc("/api/groups", s.withLogging(s.handleGroups))
+	mux.HandleFunc("/api/targets/", s.withAuth(s.handleTargetAction))
+	mux.HandleFunc("/api/groups", s.withAuth(s.withLogging(s.handleGroups)))
+	mux.HandleFunc("/api/reload", s.withAuth(s.withLogging(s.handleReload)))
 	mux.HandleFunc("/api/health", s.withLogging(s.handleHealth))
+	// Note: handleEvents upgrades to a WebSocket and logs its own connect/disconnect lines.
+	mux.HandleFunc("/api/events", s.withAuth(s.handleEvents))
+	// /ws/events is an alias for /api/events under the more conventional "ws"
+	// prefix some clients expect for upgrade endpoints; same handler, same filters.
+	mux.HandleFunc("/ws/events", s.withAuth(s.handleEvents))
+	// Unauthenticated and unlogged like a standard Prometheus scrape target,
+	// unless MetricsAuth opts into the same auth as every other endpoint.
+	if s.MetricsAuth {
+		mux.Handle("/metrics", s.withAuth(promhttp.Handler().ServeHTTP))
+	} else {
+		mux.Handle("/metrics", promhttp.Handler())
+	}
 	return mux
 }
 
+// withAuth rejects requests that don't authenticate when s.Auth is set,
+// storing the resolved Identity in the request context for downstream ACL
+// checks. With s.Auth nil (the default, unconfigured case) every request
+// passes through unauthenticated, preserving today's single-operator setup.
+func (s *Server) withAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Auth == nil {
+			handler(w, r)
+			return
+		}
+		id, err := s.Auth.Authenticate(r)
+		if err != nil {
+			log.Printf("[api] %s %s -> %s (auth denied: %v)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(http.StatusUnauthorized), err)
+			w.Header().Set("WWW-Authenticate", `Basic realm="opsnotebook"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+	}
+}
+
+// eventsUpgrader upgrades /api/events connections. CheckOrigin is permissive
+// because opsnotebook is expected to sit behind a trusted reverse proxy or
+// localhost, matching the rest of the API's lack of CORS handling today.
+var eventsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// handleEvents streams target status transitions, driver log lines, and
+// exec/proxy request summaries as JSON frames over a WebSocket. Query
+// params "target" and "tags" filter subscriptions to a single target ID or
+// a tag-glob (matched against the configured group_by composite key),
+// mirroring the filtering already used by /api/groups.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	targetID := r.URL.Query().Get("target")
+	tagGlob := r.URL.Query().Get("tags")
+
+	conn, err := eventsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[api] %s %s -> %s (websocket upgrade failed: %v)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(400), err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := s.Manager.Subscribe(targetID, tagGlob)
+	defer unsubscribe()
+
+	log.Printf("[api] %s %s -> %s (events subscription opened, target=%q tags=%q)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(101), targetID, tagGlob)
+
+	// Drain client frames (pings/closes) on a separate goroutine so a dead
+	// connection is detected promptly and the subscription is released.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(ev); err != nil {
+				log.Printf("[api] %s -> write error: %v", r.URL.Path, err)
+				return
+			}
+		}
+	}
+}
+
 // responseRecorder wraps http.ResponseWriter to capture status code
 type responseRecorder struct {
 	http.ResponseWriter
@@ -106,6 +218,27 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleReload triggers an immediate config reload, equivalent to sending
+// the process SIGHUP. It exists alongside SIGHUP so reload can be driven
+// from the UI/API without shell access to the host.
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.Reload == nil {
+		http.Error(w, "reload not configured", http.StatusNotImplemented)
+		return
+	}
+	if err := s.Reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"success":true}`))
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"status":"ok"}`))
@@ -216,6 +349,131 @@ func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(list)
 }
 
+// handleExecStream upgrades to a WebSocket and pipes Manager.StreamExec's
+// NDJSON frames to the browser as they arrive, for long-running commands
+// where waiting for DoExec's buffered response would leave the client
+// staring at a spinner. The command comes from the "command" query param
+// since a WebSocket upgrade request has no body to decode.
+func (s *Server) handleExecStream(w http.ResponseWriter, r *http.Request, state *target.State, requestID string) {
+	command := r.URL.Query().Get("command")
+	if command == "" {
+		log.Printf("[api] %s %s -> %s (missing command query param)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(400))
+		http.Error(w, "missing command query param", http.StatusBadRequest)
+		return
+	}
+
+	if s.Controller != nil {
+		var err error
+		command, err = s.Controller.InterceptExec(r.Context(), state, command)
+		if err != nil {
+			log.Printf("[api] EXEC-STREAM %s -> %s (%v)", command, colorizeStatus(http.StatusForbidden), err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	conn, err := eventsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[api] %s %s -> %s (websocket upgrade failed: %v)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(400), err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := state.ArmRequestDeadline(r.Context(), requestDeadline(r))
+	defer cancel()
+
+	frames, err := s.Manager.StreamExec(ctx, state.Config.ID, command)
+	if err != nil {
+		log.Printf("[api] EXEC-STREAM %s -> error: %v (request_id=%q)", command, err, requestID)
+		_ = conn.WriteJSON(target.ExecFrame{Err: err.Error()})
+		return
+	}
+
+	for frame := range frames {
+		if err := conn.WriteJSON(frame); err != nil {
+			log.Printf("[api] EXEC-STREAM %s -> write error: %v", command, err)
+			return
+		}
+	}
+	log.Printf("[api] EXEC-STREAM %s -> done (request_id=%q)", command, requestID)
+}
+
+// handleDriverLog serves a target's captured driver stdout/stderr: first the
+// buffered history, then (with ?follow=1) newly produced lines as they
+// arrive, reusing the same Hub the /api/events stream is built on rather
+// than a second pub/sub mechanism. Plain chunked text rather than a
+// WebSocket upgrade, since a log tail is naturally one-directional.
+func (s *Server) handleDriverLog(w http.ResponseWriter, r *http.Request, state *target.State) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		log.Printf("[api] %s %s -> %s (driver-log requires GET)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(http.StatusMethodNotAllowed))
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	writeLine := func(line target.DriverLogLine) {
+		fmt.Fprintf(w, "%s [%s] %s\n", line.Time.Format(time.RFC3339), line.Stream, line.Line)
+	}
+	for _, line := range state.DriverLogLines() {
+		writeLine(line)
+	}
+
+	if r.URL.Query().Get("follow") != "1" {
+		log.Printf("[api] %s %s -> %s", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(http.StatusOK))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return
+	}
+	flusher.Flush()
+
+	events, unsubscribe := s.Manager.Subscribe(state.Config.ID, "")
+	defer unsubscribe()
+
+	log.Printf("[api] %s %s -> %s (driver-log follow opened)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(http.StatusOK))
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Kind != target.EventLog || ev.Log == nil {
+				continue
+			}
+			writeLine(target.DriverLogLine{Time: ev.Timestamp, Stream: ev.Log.Stream, Line: ev.Log.Line})
+			flusher.Flush()
+		}
+	}
+}
+
+// requestDeadline parses the X-Request-Timeout header as a Go duration
+// (e.g. "30s", "2m"), falling back to target.DefaultRequestTimeout when the
+// header is absent or malformed.
+func requestDeadline(r *http.Request) time.Duration {
+	if raw := r.Header.Get("X-Request-Timeout"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d
+		}
+	}
+	return time.Duration(target.DefaultRequestTimeout) * time.Second
+}
+
+// writeDriverError maps a failed exec/proxy call to 504 when ctx's deadline
+// was the cause, or 502 for any other driver-side failure.
+func writeDriverError(w http.ResponseWriter, ctx context.Context, err error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		http.Error(w, "request deadline exceeded", http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
 func (s *Server) handleTargetAction(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/targets/")
 	parts := strings.Split(path, "/")
@@ -229,6 +487,8 @@ func (s *Server) handleTargetAction(w http.ResponseWriter, r *http.Request) {
 	if len(parts) > 1 {
 		action = parts[1]
 	}
+	requestID := r.Header.Get("X-Request-ID")
+	r = r.WithContext(target.WithConfirmationToken(r.Context(), r.Header.Get("X-Confirm-Token")))
 
 	state, ok := s.Manager.GetTarget(id)
 	if !ok {
@@ -237,6 +497,19 @@ func (s *Server) handleTargetAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if action == "reconnect" || action == "exec" || action == "exec-stream" || action == "proxy" || action == "driver-log" {
+		identity, _ := auth.FromContext(r.Context())
+		if !state.Config.ACL.Allows(identity, state.Config.Tags) {
+			user := "anonymous"
+			if identity != nil {
+				user = identity.User
+			}
+			log.Printf("[api] %s %s -> %s (denied: user %q not permitted to %s target %s)", colorizeMethod(r.Method), r.URL.Path, colorizeStatus(403), user, action, id)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
 	switch action {
 	case "reconnect":
 		if r.Method != http.MethodPost {
@@ -266,16 +539,39 @@ func (s *Server) handleTargetAction(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "invalid json body", http.StatusBadRequest)
 			return
 		}
-		res, code, err := state.DoExec(body.Command)
+		command := body.Command
+		if s.Controller != nil {
+			var err error
+			command, err = s.Controller.InterceptExec(r.Context(), state, command)
+			if err != nil {
+				log.Printf("[api] EXEC %s -> %s (%v)", command, colorizeStatus(http.StatusForbidden), err)
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		ctx, cancel := state.ArmRequestDeadline(r.Context(), requestDeadline(r))
+		defer cancel()
+
+		res, code, err := state.DoExec(ctx, command)
 		if err != nil {
-			log.Printf("[api] EXEC %s -> %s (error: %v)", body.Command, colorizeStatus(502), err)
-			http.Error(w, err.Error(), http.StatusBadGateway)
+			log.Printf("[api] EXEC %s -> %s (error: %v, request_id=%q)", command, colorizeStatus(502), err, requestID)
+			writeDriverError(w, ctx, err)
 			return
 		}
+		if s.Controller != nil {
+			s.Controller.AfterExec(ctx, state, command, res, code)
+		}
+		log.Printf("[api] EXEC %s -> %s (request_id=%q)", command, colorizeStatus(code), requestID)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(code)
 		w.Write(res)
 		return
+	case "exec-stream":
+		s.handleExecStream(w, r, state, requestID)
+		return
+	case "driver-log":
+		s.handleDriverLog(w, r, state)
+		return
 	case "proxy":
 		proxyPath := "/" + strings.Join(parts[2:], "/")
 		if proxyPath == "/" && r.URL.RawQuery != "" {
@@ -300,13 +596,32 @@ func (s *Server) handleTargetAction(w http.ResponseWriter, r *http.Request) {
 		if override := r.Header.Get("X-HTTP-Method-Override"); override != "" {
 			method = override
 		}
-		res, code, err := state.DoProxyRequest(method, proxyPath, body)
-		
+
+		var extraHeaders map[string]string
+		if s.Controller != nil {
+			var err error
+			method, proxyPath, body, extraHeaders, err = s.Controller.InterceptProxy(r.Context(), state, method, proxyPath, body, nil)
+			if err != nil {
+				log.Printf("[api] PROXY %s %s -> %s (%v)", colorizeMethod(method), proxyPath, colorizeStatus(http.StatusForbidden), err)
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+
+		ctx, cancel := state.ArmRequestDeadline(r.Context(), requestDeadline(r))
+		defer cancel()
+
+		res, code, err := state.DoProxyRequest(ctx, method, proxyPath, body, extraHeaders)
+
 		if err != nil {
-			log.Printf("[api] PROXY %s %s -> %s (error: %v)", colorizeMethod(method), proxyPath, colorizeStatus(502), err)
-			http.Error(w, err.Error(), 502)
+			log.Printf("[api] PROXY %s %s -> %s (error: %v, request_id=%q)", colorizeMethod(method), proxyPath, colorizeStatus(502), err, requestID)
+			writeDriverError(w, ctx, err)
 			return
 		}
+		if s.Controller != nil {
+			s.Controller.AfterProxy(ctx, state, method, proxyPath, res, code)
+		}
+		log.Printf("[api] PROXY %s %s -> %s (request_id=%q)", colorizeMethod(method), proxyPath, colorizeStatus(code), requestID)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(code)
 		w.Write(res)
@@ -0,0 +1,50 @@
+package auth
+
+// ACL restricts which identities may act on a target. A nil ACL (the zero
+// value for a target with no "acl" block in config.json) allows everyone,
+// preserving today's single-operator behavior.
+type ACL struct {
+	AllowUsers  []string          `json:"allow_users,omitempty"`
+	AllowGroups []string          `json:"allow_groups,omitempty"`
+	AllowTags   map[string]string `json:"allow_tags,omitempty"`
+}
+
+// Allows reports whether id may act on a target with the given tags under
+// this ACL. An identity matches if it appears in AllowUsers, belongs to any
+// group in AllowGroups, or the target's tags satisfy every AllowTags
+// constraint. An empty ACL denies everyone explicitly (use a nil *ACL to
+// allow everyone).
+func (a *ACL) Allows(id *Identity, tags map[string]string) bool {
+	if a == nil {
+		return true
+	}
+	if id == nil {
+		return false
+	}
+
+	for _, u := range a.AllowUsers {
+		if u == id.User {
+			return true
+		}
+	}
+	for _, allowed := range a.AllowGroups {
+		for _, g := range id.Groups {
+			if g == allowed {
+				return true
+			}
+		}
+	}
+	if len(a.AllowTags) > 0 {
+		match := true
+		for k, v := range a.AllowTags {
+			if tags[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
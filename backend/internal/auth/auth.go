@@ -0,0 +1,39 @@
+// Package auth provides pluggable request authentication for the API
+// server: HTTP Basic against an htpasswd-style file, and OIDC bearer
+// tokens validated against a discovery URL. Both resolve to an Identity
+// that handler.go's ACL checks evaluate against a TargetConfig.
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Identity is the authenticated caller extracted from a request.
+type Identity struct {
+	User   string
+	Groups []string
+}
+
+// Authenticator validates a request and returns the caller's identity, or
+// an error if the request is unauthenticated/invalid. Implementations must
+// not assume TLS is terminated upstream of them.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// contextKey is unexported so only this package can mint context keys.
+type contextKey int
+
+const identityKey contextKey = 0
+
+// WithIdentity returns a context carrying the authenticated identity.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey, id)
+}
+
+// FromContext returns the identity stored by middleware, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey).(*Identity)
+	return id, ok
+}
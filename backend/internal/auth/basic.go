@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BasicAuthenticator validates HTTP Basic credentials against an
+// htpasswd-style file (lines of "user:bcrypt-hash"). It has no notion of
+// groups; ACLs that reference allow_groups never match users authenticated
+// this way.
+type BasicAuthenticator struct {
+	path string
+
+	mu    sync.RWMutex
+	creds map[string]string // user -> bcrypt hash
+}
+
+// NewBasicAuthenticator loads credentials from path. The file is read once
+// at construction; restart the process (or re-run config reload, once
+// supported) to pick up changes.
+func NewBasicAuthenticator(path string) (*BasicAuthenticator, error) {
+	a := &BasicAuthenticator{path: path}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *BasicAuthenticator) reload() error {
+	f, err := os.Open(a.path)
+	if err != nil {
+		return fmt.Errorf("open htpasswd file: %w", err)
+	}
+	defer f.Close()
+
+	creds := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("malformed htpasswd line %q", line)
+		}
+		creds[parts[0]] = parts[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read htpasswd file: %w", err)
+	}
+
+	a.mu.Lock()
+	a.creds = creds
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *BasicAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return nil, fmt.Errorf("missing basic auth credentials")
+	}
+
+	a.mu.RLock()
+	hash, known := a.creds[user]
+	a.mu.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("unknown user %q", user)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass)); err != nil {
+		return nil, fmt.Errorf("invalid credentials for user %q", user)
+	}
+	return &Identity{User: user}, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// OIDCAuthenticator validates bearer tokens against an OIDC provider
+// discovered from discoveryURL, extracting the user and group claims
+// configured in config.json's auth.oidc block.
+type OIDCAuthenticator struct {
+	verifier    *oidc.IDTokenVerifier
+	userClaim   string
+	groupsClaim string
+}
+
+// NewOIDCAuthenticator fetches provider metadata from discoveryURL and
+// builds a verifier scoped to clientID. userClaim and groupsClaim default
+// to "email" and "groups" respectively when empty.
+func NewOIDCAuthenticator(ctx context.Context, discoveryURL, clientID, userClaim, groupsClaim string) (*OIDCAuthenticator, error) {
+	provider, err := oidc.NewProvider(ctx, discoveryURL)
+	if err != nil {
+		return nil, fmt.Errorf("discover OIDC provider: %w", err)
+	}
+	if userClaim == "" {
+		userClaim = "email"
+	}
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	return &OIDCAuthenticator{
+		verifier:    provider.Verifier(&oidc.Config{ClientID: clientID}),
+		userClaim:   userClaim,
+		groupsClaim: groupsClaim,
+	}, nil
+}
+
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, fmt.Errorf("missing bearer token")
+	}
+	rawToken := strings.TrimPrefix(header, "Bearer ")
+
+	idToken, err := a.verifier.Verify(r.Context(), rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify token: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	user, _ := claims[a.userClaim].(string)
+	if user == "" {
+		return nil, fmt.Errorf("token missing %q claim", a.userClaim)
+	}
+
+	var groups []string
+	if raw, ok := claims[a.groupsClaim].([]interface{}); ok {
+		for _, g := range raw {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+
+	return &Identity{User: user, Groups: groups}, nil
+}
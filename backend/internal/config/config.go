@@ -6,13 +6,109 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"opsnotebook/backend/internal/auth"
 )
 
 // Config represents the generic configuration file
 type Config struct {
-	GroupBy   []string       `json:"group_by,omitempty"`
-	Targets   []TargetConfig `json:"targets"`
-	Variables []VariableRule `json:"variables,omitempty"`
+	GroupBy      []string       `json:"group_by,omitempty"`
+	Targets      []TargetConfig `json:"targets"`
+	Variables    []VariableRule `json:"variables,omitempty"`
+	Auth         *AuthConfig    `json:"auth,omitempty"`
+	TrafficRules []TrafficRule  `json:"traffic_rules,omitempty"`
+
+	// TrafficAuditLog, when set, enables target.AuditController appending
+	// JSONL records of every exec and mutating proxy call to this path.
+	TrafficAuditLog string `json:"traffic_audit_log,omitempty"`
+
+	// Logging selects the sinks Manager logs connect/proxy/exec activity to.
+	// Each entry becomes one logging.Logger, fanned out via a MultiSink; an
+	// empty list defaults to a single console sink, preserving today's
+	// behavior.
+	Logging []LoggingConfig `json:"logging,omitempty"`
+
+	// Discovery, when set, starts target.Manager.Run against an external
+	// source of target definitions (on top of the static Targets list
+	// above), so fleet membership can change without a restart or a
+	// config.json edit.
+	Discovery *DiscoveryConfig `json:"discovery,omitempty"`
+
+	// MetricsAuth puts /metrics behind the configured Auth like every other
+	// endpoint. Defaults to false, since most Prometheus setups don't send
+	// this server's auth credentials on scrape requests.
+	MetricsAuth bool `json:"metrics_auth,omitempty"`
+}
+
+// DiscoveryConfig selects and configures a target.Discoverer. Type is
+// "file" (watch another JSON config file, same format as this one); Consul
+// and Kubernetes discovery exist in target.Discoverer implementations but
+// need a live client constructed in main(), not just config.
+type DiscoveryConfig struct {
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"` // "file"
+}
+
+// LoggingConfig configures one logging sink. Type selects "console"
+// (ANSI-colored stdout, the default), "file" (rotating plain-text file at
+// Path), or "json" (one JSON object per event to stdout, or to Path if
+// set). MaxSizeMB/MaxAgeHours/MaxBackups only apply to "file".
+type LoggingConfig struct {
+	Type        string `json:"type"`
+	Path        string `json:"path,omitempty"`
+	MaxSizeMB   int    `json:"max_size_mb,omitempty"`
+	MaxAgeHours int    `json:"max_age_hours,omitempty"`
+	MaxBackups  int    `json:"max_backups,omitempty"`
+}
+
+// TrafficRule is one entry of the ordered rule list evaluated by
+// target.RuleController before an exec/proxy call is dispatched. Rules are
+// evaluated in order; the first whose When matches applies its Then.
+type TrafficRule struct {
+	When TrafficMatch  `json:"when"`
+	Then TrafficAction `json:"then"`
+}
+
+// TrafficMatch selects which calls a TrafficRule applies to. Tags must all
+// be present and equal on the target; Method and PathGlob are ignored when
+// empty, and PathGlob is only meaningful for proxy calls (Path is always
+// "exec" for exec calls).
+type TrafficMatch struct {
+	Tags     map[string]string `json:"tags,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	PathGlob string            `json:"path,omitempty"`
+}
+
+// TrafficAction is what happens to a call whose When matched.
+type TrafficAction struct {
+	Deny                     bool              `json:"deny,omitempty"`
+	RewritePath              string            `json:"rewrite_path,omitempty"`
+	InjectHeader             map[string]string `json:"inject_header,omitempty"`
+	RequireConfirmationToken bool              `json:"require_confirmation_token,omitempty"`
+	Audit                    bool              `json:"audit,omitempty"`
+}
+
+// AuthConfig selects and configures the API server's authenticator. Type
+// is "none" (default), "basic", or "oidc"; the matching sub-block below it
+// is required when selected.
+type AuthConfig struct {
+	Type  string       `json:"type"`
+	Basic *BasicConfig `json:"basic,omitempty"`
+	OIDC  *OIDCConfig  `json:"oidc,omitempty"`
+}
+
+// BasicConfig configures auth.BasicAuthenticator.
+type BasicConfig struct {
+	HtpasswdFile string `json:"htpasswd_file"`
+}
+
+// OIDCConfig configures auth.OIDCAuthenticator. UserClaim and GroupsClaim
+// default to "email" and "groups" when empty.
+type OIDCConfig struct {
+	DiscoveryURL string `json:"discovery_url"`
+	ClientID     string `json:"client_id"`
+	UserClaim    string `json:"user_claim,omitempty"`
+	GroupsClaim  string `json:"groups_claim,omitempty"`
 }
 
 // DefaultGroupBy is the default grouping when not specified in config
@@ -26,13 +122,28 @@ type VariableRule struct {
 
 // TargetConfig represents a generic target system
 type TargetConfig struct {
-	ID         string                 `json:"id"`
-	Name       string                 `json:"name"`
-	Tags       map[string]string      `json:"tags"`
-	Labels     map[string]string      `json:"labels,omitempty"`
-	DriverCmd  string                 `json:"driver_cmd"`
-	Visual     map[string]string      `json:"visual,omitempty"`
-	Variables  map[string]interface{} `json:"variables,omitempty"`
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Tags      map[string]string      `json:"tags"`
+	Labels    map[string]string      `json:"labels,omitempty"`
+	DriverCmd string                 `json:"driver_cmd"`
+	Visual    map[string]string      `json:"visual,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+
+	// ACL restricts who may exec/proxy/reconnect this target. A nil ACL
+	// allows every authenticated (or, with auth disabled, every) caller.
+	ACL *auth.ACL `json:"acl,omitempty"`
+
+	// ControlTransport selects how Manager talks to this target's driver
+	// control plane: "unix" (a per-target Unix domain socket, the default
+	// on Linux/macOS) or "tcp" (a loopback port, the only option on other
+	// platforms).
+	ControlTransport string `json:"control_transport,omitempty"`
+
+	// GracePeriodSeconds bounds how long Disconnect waits after SIGTERM
+	// before escalating to SIGKILL. Zero or unset falls back to
+	// target.DefaultGracePeriod.
+	GracePeriodSeconds int `json:"grace_period_seconds,omitempty"`
 }
 
 // Load reads a config file and optionally filters targets by pattern
@@ -75,12 +186,7 @@ func LoadWithPattern(path string, pattern string) (*Config, error) {
 func filterTargets(targets []TargetConfig, pattern string, groupBy []string) []TargetConfig {
 	var filtered []TargetConfig
 	for _, t := range targets {
-		keyParts := make([]string, len(groupBy))
-		for i, key := range groupBy {
-			keyParts[i] = t.Tags[key]
-		}
-		fullKey := strings.Join(keyParts, ":")
-		if matchGlob(pattern, fullKey) {
+		if matchGlob(pattern, GroupKey(t.Tags, groupBy)) {
 			filtered = append(filtered, t)
 		}
 	}
@@ -93,6 +199,25 @@ func matchGlob(pattern, value string) bool {
 	return matched
 }
 
+// MatchGlob matches a pattern against a value using the same glob rules as
+// LoadWithPattern's target filtering. Exported so other packages (e.g.
+// internal/target's subscription filters) can match against the same
+// composite group_by keys without duplicating the glob semantics.
+func MatchGlob(pattern, value string) bool {
+	return matchGlob(pattern, value)
+}
+
+// GroupKey builds the composite key used for pattern matching and grouping,
+// joining the tag values named by groupBy with ":" (e.g. ["environment",
+// "region", "name"] -> "staging:sg:myapp").
+func GroupKey(tags map[string]string, groupBy []string) string {
+	parts := make([]string, len(groupBy))
+	for i, key := range groupBy {
+		parts[i] = tags[key]
+	}
+	return strings.Join(parts, ":")
+}
+
 func (c *Config) Validate() error {
 	seenIDs := make(map[string]bool)
 	for _, t := range c.Targets {
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"log"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch watches path for writes/renames (the pattern most editors and
+// `kubectl cp`/configmap mounts use when replacing a file) and invokes
+// onChange with the freshly loaded Config each time. It returns a stop
+// function that closes the underlying watcher; errors loading the new
+// config are logged and skipped rather than propagated, since a transient
+// half-written file shouldn't crash the watch loop.
+func Watch(path string, onChange func(*Config)) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(path); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if event.Op&fsnotify.Rename != 0 {
+					// Editors that save-by-rename replace the watched inode;
+					// re-add so we keep watching the new file at this path.
+					_ = watcher.Add(path)
+				}
+				cfg, err := Load(path)
+				if err != nil {
+					log.Printf("[config] reload of %s failed, keeping previous config: %v", path, err)
+					continue
+				}
+				onChange(cfg)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[config] watch error: %v", err)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		watcher.Close()
+	}, nil
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+)
+
+// ANSI color codes. Duplicated from internal/target and internal/api rather
+// than shared, matching this repo's existing convention of keeping each
+// package's small formatting helpers self-contained.
+const (
+	colorReset   = "\033[0m"
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorMagenta = "\033[35m"
+	colorCyan    = "\033[36m"
+)
+
+func colorizeMethod(method string) string {
+	switch method {
+	case "GET":
+		return colorGreen + method + colorReset
+	case "POST":
+		return colorBlue + method + colorReset
+	case "PUT":
+		return colorYellow + method + colorReset
+	case "DELETE":
+		return colorRed + method + colorReset
+	case "PATCH":
+		return colorMagenta + method + colorReset
+	case "HEAD":
+		return colorCyan + method + colorReset
+	default:
+		return method
+	}
+}
+
+func colorizeStatus(code int) string {
+	switch {
+	case code >= 200 && code < 300:
+		return colorGreen + fmt.Sprintf("%d", code) + colorReset
+	case code >= 300 && code < 400:
+		return colorCyan + fmt.Sprintf("%d", code) + colorReset
+	case code >= 400 && code < 500:
+		return colorYellow + fmt.Sprintf("%d", code) + colorReset
+	case code >= 500:
+		return colorRed + fmt.Sprintf("%d", code) + colorReset
+	default:
+		return fmt.Sprintf("%d", code)
+	}
+}
+
+// ConsoleSink renders Records as the human-readable, ANSI-colored lines
+// Manager used to emit directly via log.Printf. It's the default Logger
+// when none is configured, so existing deployments see unchanged output.
+type ConsoleSink struct{}
+
+func (ConsoleSink) Log(r Record) {
+	prefix := fmt.Sprintf("[%s]", r.TargetID)
+	if r.TargetID == "" {
+		prefix = "[driver]"
+	}
+
+	switch r.Event {
+	case "proxy":
+		if r.Err != "" {
+			log.Printf("%s PROXY %s %s -> error: %s (took %dms)", prefix, colorizeMethod(r.Method), r.Path, r.Err, r.DurationMS)
+			return
+		}
+		log.Printf("%s PROXY %s %s -> %s (took %dms)", prefix, colorizeMethod(r.Method), r.Path, colorizeStatus(r.Status), r.DurationMS)
+	case "exec", "exec-stream":
+		if r.Err != "" {
+			log.Printf("%s EXEC %s -> error: %s (took %dms)", prefix, r.Command, r.Err, r.DurationMS)
+			return
+		}
+		log.Printf("%s EXEC %s -> %s (took %dms)", prefix, r.Command, colorizeStatus(r.Status), r.DurationMS)
+	case "driver":
+		if r.Err != "" {
+			log.Printf("%s %s %s -> %s%s%s", prefix, colorizeMethod(r.Method), r.Path, colorYellow, r.Err, colorReset)
+			return
+		}
+		log.Printf("%s %s %s -> %s", prefix, colorizeMethod(r.Method), r.Path, colorizeStatus(r.Status))
+	case "error":
+		log.Printf("%s Error: %s", prefix, r.Err)
+	default:
+		log.Printf("%s %s", prefix, r.Message)
+	}
+}
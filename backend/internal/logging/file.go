@@ -0,0 +1,175 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSink writes plain-text log lines to Path, rotating the file once it
+// exceeds MaxSizeMB (or, on the next write after it was last rotated,
+// MaxAge) and keeping at most MaxBackups rotated files around. A zero value
+// for any limit disables that trigger.
+type FileSink struct {
+	Path       string
+	MaxSizeMB  int
+	MaxAge     time.Duration
+	MaxBackups int
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewFileSink opens (creating if necessary) the file at path for appending.
+func NewFileSink(path string, maxSizeMB, maxBackups int, maxAge time.Duration) (*FileSink, error) {
+	s := &FileSink{
+		Path:       path,
+		MaxSizeMB:  maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("logging: open %s: %w", s.Path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("logging: stat %s: %w", s.Path, err)
+	}
+	s.file = f
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *FileSink) Log(r Record) {
+	line := formatPlain(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shouldRotateLocked() {
+		if err := s.rotateLocked(); err != nil {
+			fmt.Fprintf(os.Stderr, "logging: rotate %s: %v\n", s.Path, err)
+		}
+	}
+
+	n, err := s.file.WriteString(line + "\n")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logging: write %s: %v\n", s.Path, err)
+		return
+	}
+	s.size += int64(n)
+}
+
+func (s *FileSink) shouldRotateLocked() bool {
+	if s.MaxSizeMB > 0 && s.size >= int64(s.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	if s.MaxAge > 0 && time.Since(s.openedAt) >= s.MaxAge {
+		return true
+	}
+	return false
+}
+
+// rotateLocked renames the current file aside with a timestamp suffix,
+// opens a fresh one, and prunes backups beyond MaxBackups / older than
+// MaxAge. Caller must hold s.mu.
+func (s *FileSink) rotateLocked() error {
+	if s.file != nil {
+		s.file.Close()
+	}
+
+	backup := fmt.Sprintf("%s.%s", s.Path, time.Now().UTC().Format("20060102T150405"))
+	if err := os.Rename(s.Path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	s.pruneBackups()
+	return nil
+}
+
+func (s *FileSink) pruneBackups() {
+	dir := filepath.Dir(s.Path)
+	base := filepath.Base(s.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, base+".") {
+			backups = append(backups, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(backups) // timestamp suffix sorts lexicographically == chronologically
+
+	if s.MaxAge > 0 {
+		cutoff := time.Now().Add(-s.MaxAge)
+		kept := backups[:0]
+		for _, b := range backups {
+			if info, err := os.Stat(b); err == nil && info.ModTime().Before(cutoff) {
+				os.Remove(b)
+				continue
+			}
+			kept = append(kept, b)
+		}
+		backups = kept
+	}
+
+	if s.MaxBackups > 0 && len(backups) > s.MaxBackups {
+		for _, b := range backups[:len(backups)-s.MaxBackups] {
+			os.Remove(b)
+		}
+	}
+}
+
+func formatPlain(r Record) string {
+	ts := r.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	prefix := fmt.Sprintf("%s [%s]", ts, r.TargetID)
+	if r.TargetID == "" {
+		prefix = fmt.Sprintf("%s [driver]", ts)
+	}
+
+	switch r.Event {
+	case "proxy":
+		if r.Err != "" {
+			return fmt.Sprintf("%s PROXY %s %s -> error: %s (took %dms)", prefix, r.Method, r.Path, r.Err, r.DurationMS)
+		}
+		return fmt.Sprintf("%s PROXY %s %s -> %d (took %dms)", prefix, r.Method, r.Path, r.Status, r.DurationMS)
+	case "exec", "exec-stream":
+		if r.Err != "" {
+			return fmt.Sprintf("%s EXEC %s -> error: %s (took %dms)", prefix, r.Command, r.Err, r.DurationMS)
+		}
+		return fmt.Sprintf("%s EXEC %s -> %d (took %dms)", prefix, r.Command, r.Status, r.DurationMS)
+	case "driver":
+		if r.Err != "" {
+			return fmt.Sprintf("%s %s %s -> %s", prefix, r.Method, r.Path, r.Err)
+		}
+		return fmt.Sprintf("%s %s %s -> %d", prefix, r.Method, r.Path, r.Status)
+	case "error":
+		return fmt.Sprintf("%s Error: %s", prefix, r.Err)
+	default:
+		return fmt.Sprintf("%s %s", prefix, r.Message)
+	}
+}
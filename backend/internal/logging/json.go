@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// jsonRecord is the on-the-wire shape JSONSink writes, one per line, suitable
+// for ingestion by a log aggregator. Message carries events that don't fit
+// the method/path/status/duration shape (e.g. "connect", "reload").
+type jsonRecord struct {
+	Time       string `json:"ts"`
+	Level      Level  `json:"level"`
+	TargetID   string `json:"target_id"`
+	Event      string `json:"event"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Command    string `json:"command,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Err        string `json:"err,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// JSONSink writes one JSON object per Record to Writer (os.Stdout by
+// default), for feeding log aggregators that expect structured input.
+type JSONSink struct {
+	Writer io.Writer
+
+	mu sync.Mutex
+}
+
+// NewJSONSink returns a JSONSink writing to os.Stdout.
+func NewJSONSink() *JSONSink {
+	return &JSONSink{Writer: os.Stdout}
+}
+
+func (s *JSONSink) Log(r Record) {
+	w := s.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+
+	rec := jsonRecord{
+		Time:       r.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:      r.Level,
+		TargetID:   r.TargetID,
+		Event:      r.Event,
+		Method:     r.Method,
+		Path:       r.Path,
+		Command:    r.Command,
+		Status:     r.Status,
+		DurationMS: r.DurationMS,
+		Err:        r.Err,
+		Message:    r.Message,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := json.NewEncoder(w)
+	_ = enc.Encode(rec)
+}
@@ -0,0 +1,40 @@
+// Package logging provides a small pluggable logging abstraction used by
+// internal/target to replace ad-hoc log.Printf calls. A Logger receives a
+// structured Record per event; sinks decide how (or whether) to render it.
+package logging
+
+import "time"
+
+// Level is the severity of a logged Record.
+type Level string
+
+const (
+	LevelInfo  Level = "info"
+	LevelError Level = "error"
+)
+
+// Record is a single log entry. Event identifies what happened; Method,
+// Path, Command, Status, DurationMS, and Err are populated for the events
+// they're relevant to and left zero otherwise. Message carries free-text
+// detail for events that don't fit the structured fields (e.g. driver
+// handshake lines, reload summaries) and is the only field ConsoleSink
+// falls back to when the structured fields are empty.
+type Record struct {
+	Time       time.Time
+	Level      Level
+	TargetID   string
+	Event      string // "connect", "disconnect", "error", "proxy", "exec", "exec-stream", "driver", "reload"
+	Method     string
+	Path       string
+	Command    string
+	Status     int
+	DurationMS int64
+	Err        string
+	Message    string
+}
+
+// Logger receives log Records. Implementations must be safe for concurrent
+// use, since Manager and its States log from multiple goroutines.
+type Logger interface {
+	Log(Record)
+}
@@ -0,0 +1,12 @@
+package logging
+
+// MultiSink fans a Record out to every Logger in order, letting e.g. a
+// ConsoleSink (for the operator's terminal) and a JSONSink (for an
+// aggregator) run side by side.
+type MultiSink []Logger
+
+func (m MultiSink) Log(r Record) {
+	for _, sink := range m {
+		sink.Log(r)
+	}
+}
@@ -0,0 +1,75 @@
+// Package metrics holds the Prometheus collectors opsnotebook exposes on
+// /metrics. It is kept separate from internal/target so the instrumentation
+// can be exercised without standing up the HTTP server.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// TargetUp reports 1 when a target's driver connection is up, 0
+	// otherwise, labeled by the fields the UI already groups targets by.
+	TargetUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opsnotebook_target_up",
+		Help: "Whether a target's driver connection is currently up (1) or not (0).",
+	}, []string{"id", "name", "env", "region", "variant"})
+
+	// ReconnectAttempts counts every Manager.Connect call per target,
+	// including the initial connect and each auto-reconnect.
+	ReconnectAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "opsnotebook_reconnect_attempts_total",
+		Help: "Total number of connect attempts made for a target.",
+	}, []string{"id"})
+
+	// ReconnectFailures counts connect attempts that ended in setError.
+	ReconnectFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "opsnotebook_reconnect_failures_total",
+		Help: "Total number of connect attempts that failed for a target.",
+	}, []string{"id"})
+
+	// ProxyDuration tracks DoProxyRequest latency by target and HTTP method.
+	ProxyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "opsnotebook_proxy_request_duration_seconds",
+		Help:    "Latency of proxied requests to target drivers.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"id", "method"})
+
+	// ExecDuration tracks DoExec latency by target.
+	ExecDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "opsnotebook_exec_duration_seconds",
+		Help:    "Latency of exec calls dispatched to target drivers.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"id"})
+
+	// ActiveConnections is the manager-wide count of targets currently connected.
+	ActiveConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "opsnotebook_active_connections",
+		Help: "Number of targets currently in the connected state.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(TargetUp, ReconnectAttempts, ReconnectFailures, ProxyDuration, ExecDuration, ActiveConnections)
+}
+
+// SetTargetUp records whether a target is currently connected.
+func SetTargetUp(id, name, env, region, variant string, up bool) {
+	var v float64
+	if up {
+		v = 1
+	}
+	TargetUp.WithLabelValues(id, name, env, region, variant).Set(v)
+}
+
+// ObserveProxy records the duration of a completed proxy request.
+func ObserveProxy(id, method string, d time.Duration) {
+	ProxyDuration.WithLabelValues(id, method).Observe(d.Seconds())
+}
+
+// ObserveExec records the duration of a completed exec call.
+func ObserveExec(id string, d time.Duration) {
+	ExecDuration.WithLabelValues(id).Observe(d.Seconds())
+}
@@ -0,0 +1,173 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"opsnotebook/backend/internal/auth"
+)
+
+// DenyProdMutatingController denies exec calls and DELETE proxy calls
+// against any target tagged environment=prod, as a default guardrail
+// against fat-fingered destructive operations. It does not touch GET/POST/
+// PUT/PATCH proxy calls; pair it with RuleController for finer-grained policy.
+type DenyProdMutatingController struct{}
+
+func (DenyProdMutatingController) InterceptExec(ctx context.Context, state *State, command string) (string, error) {
+	state.mu.RLock()
+	env := state.Config.Tags["environment"]
+	state.mu.RUnlock()
+	if env == "prod" {
+		return command, &DenyError{Reason: "exec is denied by default on environment=prod targets"}
+	}
+	return command, nil
+}
+
+func (DenyProdMutatingController) InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (string, string, []byte, map[string]string, error) {
+	state.mu.RLock()
+	env := state.Config.Tags["environment"]
+	state.mu.RUnlock()
+	if env == "prod" && method == "DELETE" {
+		return method, path, body, headers, &DenyError{Reason: "DELETE is denied by default on environment=prod targets"}
+	}
+	return method, path, body, headers, nil
+}
+
+func (DenyProdMutatingController) AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int) {
+}
+
+func (DenyProdMutatingController) AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int) {
+}
+
+// auditMutatingMethods are the proxy HTTP methods AuditController treats as
+// mutating (and therefore worth auditing); GET/HEAD are read-only and skipped.
+var auditMutatingMethods = map[string]bool{
+	"POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+}
+
+// auditRecord is one line of the append-only JSONL audit log.
+type auditRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	TargetID  string    `json:"target_id"`
+	User      string    `json:"user"`
+	Kind      string    `json:"kind"` // "exec" or "proxy"
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Command   string    `json:"command,omitempty"`
+	Status    int       `json:"status"`
+}
+
+// AuditController appends a JSON line per mutating call (every exec, and
+// proxy calls using a method in auditMutatingMethods) to an append-only
+// file, recording the caller's identity from the request context.
+type AuditController struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewAuditController opens (creating if needed) path for appending.
+func NewAuditController(path string) (*AuditController, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return &AuditController{file: f}, nil
+}
+
+func (a *AuditController) write(rec auditRecord) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, _ = a.file.Write(line)
+}
+
+func (a *AuditController) identity(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok && id != nil {
+		return id.User
+	}
+	return "anonymous"
+}
+
+func (a *AuditController) InterceptExec(ctx context.Context, state *State, command string) (string, error) {
+	return command, nil
+}
+
+func (a *AuditController) InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (string, string, []byte, map[string]string, error) {
+	return method, path, body, headers, nil
+}
+
+func (a *AuditController) AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int) {
+	state.mu.RLock()
+	targetID := state.Config.ID
+	state.mu.RUnlock()
+	a.write(auditRecord{
+		Timestamp: time.Now(),
+		TargetID:  targetID,
+		User:      a.identity(ctx),
+		Kind:      "exec",
+		Command:   command,
+		Status:    status,
+	})
+}
+
+func (a *AuditController) AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int) {
+	if !auditMutatingMethods[method] {
+		return
+	}
+	state.mu.RLock()
+	targetID := state.Config.ID
+	state.mu.RUnlock()
+	a.write(auditRecord{
+		Timestamp: time.Now(),
+		TargetID:  targetID,
+		User:      a.identity(ctx),
+		Kind:      "proxy",
+		Method:    method,
+		Path:      path,
+		Status:    status,
+	})
+}
+
+// HeaderInjectionController stamps every proxy request with a tracing
+// header so a request can be correlated across opsnotebook's logs and the
+// target's own. It leaves exec calls untouched since DoExec doesn't carry
+// arbitrary headers.
+type HeaderInjectionController struct {
+	HeaderName string // defaults to "X-Trace-Id" when empty
+}
+
+func (h HeaderInjectionController) headerName() string {
+	if h.HeaderName == "" {
+		return "X-Trace-Id"
+	}
+	return h.HeaderName
+}
+
+func (h HeaderInjectionController) InterceptExec(ctx context.Context, state *State, command string) (string, error) {
+	return command, nil
+}
+
+func (h HeaderInjectionController) InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (string, string, []byte, map[string]string, error) {
+	if headers == nil {
+		headers = make(map[string]string, 1)
+	}
+	if _, exists := headers[h.headerName()]; !exists {
+		headers[h.headerName()] = fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return method, path, body, headers, nil
+}
+
+func (h HeaderInjectionController) AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int) {
+}
+
+func (h HeaderInjectionController) AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int) {
+}
@@ -6,3 +6,20 @@ const (
 	StatusConnected    = "connected"
 	StatusError        = "error"
 )
+
+const (
+	// DefaultRequestTimeout is the exec/proxy deadline used when a caller
+	// doesn't send an X-Request-Timeout header. Seconds, for backwards
+	// compatibility with callers that read it as a plain int.
+	DefaultRequestTimeout = 3600
+
+	// DefaultGracePeriod is how long terminateDriverCmd waits after SIGTERM
+	// before escalating to SIGKILL, when a TargetConfig doesn't set
+	// GracePeriodSeconds. Seconds, matching DefaultRequestTimeout.
+	DefaultGracePeriod = 10
+
+	// driverLogCapacity bounds how many stdout/stderr lines GET
+	// /api/targets/{id}/driver-log can replay from history; older lines are
+	// dropped once a target's buffer is full.
+	driverLogCapacity = 500
+)
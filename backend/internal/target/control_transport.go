@@ -0,0 +1,25 @@
+package target
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// newControlClient returns an http.Client for the driver's control plane.
+// For "unix" it dials socketPath regardless of the URL host/port callers
+// pass in (the URL is only used to form request paths like "/status");
+// for anything else it's a plain client dialing TCP as usual.
+func newControlClient(transport, socketPath string) *http.Client {
+	if transport != "unix" {
+		return &http.Client{}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+}
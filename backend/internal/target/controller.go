@@ -0,0 +1,68 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TrafficController intercepts exec/proxy calls against a target before
+// they're dispatched to DoExec/DoProxyRequest, and is notified of the
+// outcome afterward. Intercept methods return the (possibly rewritten)
+// call; a non-nil error aborts dispatch (e.g. a deny rule). After hooks are
+// best-effort and must not block the response to the caller for long.
+type TrafficController interface {
+	InterceptExec(ctx context.Context, state *State, command string) (string, error)
+	InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (newMethod, newPath string, newBody []byte, newHeaders map[string]string, err error)
+	AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int)
+	AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int)
+}
+
+// Chain runs a list of TrafficControllers in order. Intercept calls feed
+// each controller's output into the next; the first Deny-style error stops
+// the chain. After hooks are fanned out to every controller regardless.
+type Chain []TrafficController
+
+func (c Chain) InterceptExec(ctx context.Context, state *State, command string) (string, error) {
+	for _, ctrl := range c {
+		var err error
+		command, err = ctrl.InterceptExec(ctx, state, command)
+		if err != nil {
+			return command, err
+		}
+	}
+	return command, nil
+}
+
+func (c Chain) InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (string, string, []byte, map[string]string, error) {
+	for _, ctrl := range c {
+		var err error
+		method, path, body, headers, err = ctrl.InterceptProxy(ctx, state, method, path, body, headers)
+		if err != nil {
+			return method, path, body, headers, err
+		}
+	}
+	return method, path, body, headers, nil
+}
+
+func (c Chain) AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int) {
+	for _, ctrl := range c {
+		ctrl.AfterExec(ctx, state, command, resp, status)
+	}
+}
+
+func (c Chain) AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int) {
+	for _, ctrl := range c {
+		ctrl.AfterProxy(ctx, state, method, path, resp, status)
+	}
+}
+
+// DenyError is returned by a TrafficController to reject a call. The API
+// layer maps it to 403 rather than the 502 used for driver-side failures.
+type DenyError struct {
+	Reason string
+}
+
+func (e *DenyError) Error() string {
+	return fmt.Sprintf("denied by traffic controller: %s", e.Reason)
+}
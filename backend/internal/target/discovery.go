@@ -0,0 +1,122 @@
+package target
+
+import (
+	"context"
+	"sync"
+
+	"opsnotebook/backend/internal/config"
+)
+
+// DiscoveryEventKind identifies how a DiscoveryEvent's Target changed.
+type DiscoveryEventKind string
+
+const (
+	DiscoveryAdded   DiscoveryEventKind = "added"
+	DiscoveryUpdated DiscoveryEventKind = "updated"
+	DiscoveryRemoved DiscoveryEventKind = "removed"
+)
+
+// DiscoveryEvent is one target membership change reported by a Discoverer.
+type DiscoveryEvent struct {
+	Kind   DiscoveryEventKind
+	Target config.TargetConfig
+}
+
+// Discoverer watches an external source of target definitions and reports
+// additions, changes, and removals as they happen, rather than requiring a
+// restart (or a full config reload) to pick them up. Watch must close its
+// returned channel once ctx is canceled.
+type Discoverer interface {
+	Watch(ctx context.Context) <-chan DiscoveryEvent
+}
+
+// Run merges events from every discoverer and reconciles them into the
+// running target set incrementally, until ctx is canceled. Unlike Reload
+// (which diffs one whole config snapshot against another), Run applies each
+// event as it arrives, so target membership tracks a live source (a file, a
+// Consul prefix, a Kubernetes ConfigMap) without a restart.
+func (m *Manager) Run(ctx context.Context, discoverers ...Discoverer) {
+	events := make(chan DiscoveryEvent)
+
+	var wg sync.WaitGroup
+	for _, d := range discoverers {
+		wg.Add(1)
+		go func(d Discoverer) {
+			defer wg.Done()
+			for ev := range d.Watch(ctx) {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(d)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			m.applyDiscoveryEvent(ev)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// applyDiscoveryEvent reconciles a single DiscoveryEvent into m.targets,
+// mirroring Reload's Connect/Disconnect rules but for one target rather
+// than a whole config diff.
+func (m *Manager) applyDiscoveryEvent(ev DiscoveryEvent) {
+	switch ev.Kind {
+	case DiscoveryRemoved:
+		m.mu.Lock()
+		st, ok := m.targets[ev.Target.ID]
+		if ok {
+			delete(m.targets, ev.Target.ID)
+		}
+		m.mu.Unlock()
+		if ok {
+			m.Disconnect(st)
+		}
+
+	case DiscoveryAdded:
+		m.mu.Lock()
+		st, exists := m.targets[ev.Target.ID]
+		if !exists {
+			st = &State{Config: ev.Target, Status: StatusDisconnected, hub: m.hub, logger: m.logger, driverLog: newDriverLogBuffer()}
+			m.targets[ev.Target.ID] = st
+		}
+		m.mu.Unlock()
+		if !exists {
+			m.Connect(st)
+		}
+
+	case DiscoveryUpdated:
+		m.mu.Lock()
+		st, exists := m.targets[ev.Target.ID]
+		var restart bool
+		if exists {
+			st.mu.Lock()
+			restart = targetChanged(st.Config, ev.Target)
+			st.Config = ev.Target
+			st.mu.Unlock()
+		} else {
+			st = &State{Config: ev.Target, Status: StatusDisconnected, hub: m.hub, logger: m.logger, driverLog: newDriverLogBuffer()}
+			m.targets[ev.Target.ID] = st
+		}
+		m.mu.Unlock()
+		if !exists {
+			m.Connect(st)
+		} else if restart {
+			m.Disconnect(st)
+			m.Connect(st)
+		}
+	}
+}
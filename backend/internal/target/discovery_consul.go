@@ -0,0 +1,84 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+
+	"opsnotebook/backend/internal/config"
+)
+
+// ConsulDiscoverer watches a Consul KV prefix (e.g. "opsnotebook/targets/")
+// where each key's value is a JSON-encoded config.TargetConfig, using
+// blocking queries so updates are pushed rather than polled. The key's
+// final path segment is ignored; TargetConfig.ID is taken from the decoded
+// value, matching how FileDiscoverer keys off the config file's own IDs.
+type ConsulDiscoverer struct {
+	Client *consulapi.Client
+	Prefix string
+}
+
+func (d ConsulDiscoverer) Watch(ctx context.Context) <-chan DiscoveryEvent {
+	out := make(chan DiscoveryEvent)
+
+	go func() {
+		defer close(out)
+
+		last := make(map[string]config.TargetConfig)
+		var waitIndex uint64
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			pairs, meta, err := d.Client.KV().List(d.Prefix, (&consulapi.QueryOptions{
+				WaitIndex: waitIndex,
+				WaitTime:  5 * time.Minute,
+			}).WithContext(ctx))
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("[discovery] consul KV list %s failed: %v", d.Prefix, err)
+				time.Sleep(5 * time.Second)
+				continue
+			}
+			waitIndex = meta.LastIndex
+
+			current := make(map[string]config.TargetConfig, len(pairs))
+			for _, pair := range pairs {
+				var t config.TargetConfig
+				if err := json.Unmarshal(pair.Value, &t); err != nil {
+					log.Printf("[discovery] consul key %s: invalid TargetConfig: %v", pair.Key, err)
+					continue
+				}
+				current[t.ID] = t
+			}
+
+			for id, t := range current {
+				old, existed := last[id]
+				switch {
+				case !existed:
+					out <- DiscoveryEvent{Kind: DiscoveryAdded, Target: t}
+				case !reflect.DeepEqual(old, t):
+					out <- DiscoveryEvent{Kind: DiscoveryUpdated, Target: t}
+				}
+			}
+			for id, t := range last {
+				if _, stillPresent := current[id]; !stillPresent {
+					out <- DiscoveryEvent{Kind: DiscoveryRemoved, Target: t}
+				}
+			}
+			last = current
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,61 @@
+package target
+
+import (
+	"context"
+	"reflect"
+
+	"opsnotebook/backend/internal/config"
+)
+
+// FileDiscoverer watches a JSON config file (the same format config.Load
+// reads) and diffs its target list against the previous snapshot it saw,
+// turning additions, removals, and field changes into DiscoveryEvents. It's
+// built on config.Watch, so it inherits that function's rename-on-save
+// handling for editors that replace rather than truncate the file.
+type FileDiscoverer struct {
+	Path string
+}
+
+func (d FileDiscoverer) Watch(ctx context.Context) <-chan DiscoveryEvent {
+	out := make(chan DiscoveryEvent)
+
+	go func() {
+		defer close(out)
+
+		last := make(map[string]config.TargetConfig)
+		diff := func(cfg *config.Config) {
+			current := make(map[string]config.TargetConfig, len(cfg.Targets))
+			for _, t := range cfg.Targets {
+				current[t.ID] = t
+			}
+			for id, t := range current {
+				old, existed := last[id]
+				switch {
+				case !existed:
+					out <- DiscoveryEvent{Kind: DiscoveryAdded, Target: t}
+				case !reflect.DeepEqual(old, t):
+					out <- DiscoveryEvent{Kind: DiscoveryUpdated, Target: t}
+				}
+			}
+			for id, t := range last {
+				if _, stillPresent := current[id]; !stillPresent {
+					out <- DiscoveryEvent{Kind: DiscoveryRemoved, Target: t}
+				}
+			}
+			last = current
+		}
+
+		if cfg, err := config.Load(d.Path); err == nil {
+			diff(cfg)
+		}
+
+		stop, err := config.Watch(d.Path, diff)
+		if err != nil {
+			return
+		}
+		defer stop()
+		<-ctx.Done()
+	}()
+
+	return out
+}
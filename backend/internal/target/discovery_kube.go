@@ -0,0 +1,97 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"opsnotebook/backend/internal/config"
+)
+
+// KubeDiscoverer watches a single ConfigMap and treats each of its Data
+// entries as a JSON-encoded config.TargetConfig, so a fleet's membership
+// can be managed with `kubectl apply`/a GitOps pipeline instead of editing
+// opsnotebook's own config file. The entry's key is ignored in favor of the
+// decoded TargetConfig's own ID, matching FileDiscoverer/ConsulDiscoverer.
+type KubeDiscoverer struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+}
+
+func (d KubeDiscoverer) Watch(ctx context.Context) <-chan DiscoveryEvent {
+	out := make(chan DiscoveryEvent)
+
+	go func() {
+		defer close(out)
+
+		last := make(map[string]config.TargetConfig)
+		diff := func(cm *corev1.ConfigMap) {
+			current := make(map[string]config.TargetConfig, len(cm.Data))
+			for key, value := range cm.Data {
+				var t config.TargetConfig
+				if err := json.Unmarshal([]byte(value), &t); err != nil {
+					log.Printf("[discovery] configmap %s/%s key %s: invalid TargetConfig: %v", cm.Namespace, cm.Name, key, err)
+					continue
+				}
+				current[t.ID] = t
+			}
+
+			for id, t := range current {
+				old, existed := last[id]
+				switch {
+				case !existed:
+					out <- DiscoveryEvent{Kind: DiscoveryAdded, Target: t}
+				case !reflect.DeepEqual(old, t):
+					out <- DiscoveryEvent{Kind: DiscoveryUpdated, Target: t}
+				}
+			}
+			for id, t := range last {
+				if _, stillPresent := current[id]; !stillPresent {
+					out <- DiscoveryEvent{Kind: DiscoveryRemoved, Target: t}
+				}
+			}
+			last = current
+		}
+
+		cms := d.Client.CoreV1().ConfigMaps(d.Namespace)
+		if cm, err := cms.Get(ctx, d.Name, metav1.GetOptions{}); err == nil {
+			diff(cm)
+		}
+
+		fieldSelector := "metadata.name=" + d.Name
+		watcher, err := cms.Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+		if err != nil {
+			log.Printf("[discovery] watch configmap %s/%s failed: %v", d.Namespace, d.Name, err)
+			return
+		}
+		defer watcher.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+				cm, ok := event.Object.(*corev1.ConfigMap)
+				if !ok {
+					continue
+				}
+				if event.Type == "DELETED" {
+					diff(&corev1.ConfigMap{})
+					continue
+				}
+				diff(cm)
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,103 @@
+package target
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"opsnotebook/backend/internal/logging"
+)
+
+// DriverLogLine is one line of captured driver stdout/stderr, as returned by
+// GET /api/targets/{id}/driver-log.
+type DriverLogLine struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"` // "stdout" or "stderr"
+	Line   string    `json:"line"`
+}
+
+// driverLogBuffer is a fixed-capacity ring buffer of a target's driver
+// output, so a client can fetch recent history without having been
+// subscribed on the Hub when it happened. It survives across reconnects
+// since it's owned by the long-lived State, not the per-connection cmd.
+type driverLogBuffer struct {
+	mu    sync.Mutex
+	lines []DriverLogLine
+	start int // index of the oldest line once the buffer has wrapped
+}
+
+func newDriverLogBuffer() *driverLogBuffer {
+	return &driverLogBuffer{lines: make([]DriverLogLine, 0, driverLogCapacity)}
+}
+
+func (b *driverLogBuffer) append(line DriverLogLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.lines) < driverLogCapacity {
+		b.lines = append(b.lines, line)
+		return
+	}
+	b.lines[b.start] = line
+	b.start = (b.start + 1) % driverLogCapacity
+}
+
+// snapshot returns the buffered lines in chronological order.
+func (b *driverLogBuffer) snapshot() []DriverLogLine {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]DriverLogLine, 0, len(b.lines))
+	out = append(out, b.lines[b.start:]...)
+	out = append(out, b.lines[:b.start]...)
+	return out
+}
+
+// DriverLogLines returns the target's buffered driver stdout/stderr history.
+func (s *State) DriverLogLines() []DriverLogLine {
+	return s.driverLog.snapshot()
+}
+
+// drainDriverOutput copies r (the driver cmd's stdout or stderr pipe) into
+// state's ring-buffered driver log and the event hub, one line at a time,
+// until r returns EOF (the process exited) or a read error. Callers must
+// wait for this to return before calling cmd.Wait, since Wait closes the
+// pipe once the process exits and reading from a closed pipe is undefined.
+//
+// Uses bufio.Reader.ReadString rather than bufio.Scanner: Scanner enforces a
+// max token size and just stops (silently, unless a caller checks Err())
+// once a single line exceeds it, which would re-open the pipe-buffer-fills-
+// and-driver-hangs class of bug this type exists to close -- just via one
+// long line instead of an undrained pipe. ReadString has no such cap.
+func (m *Manager) drainDriverOutput(state *State, stream string, r io.Reader) {
+	reader := bufio.NewReader(r)
+	for {
+		text, err := reader.ReadString('\n')
+		if text != "" {
+			line := DriverLogLine{Time: time.Now(), Stream: stream, Line: strings.TrimSuffix(text, "\n")}
+			state.driverLog.append(line)
+
+			state.mu.RLock()
+			targetID := state.Config.ID
+			tags := state.Config.Tags
+			state.mu.RUnlock()
+
+			m.hub.publish(Event{
+				Kind:      EventLog,
+				TargetID:  targetID,
+				Timestamp: line.Time,
+				Log:       &LogEvent{Stream: line.Stream, Line: line.Line},
+			}, tags)
+		}
+		if err != nil {
+			if err != io.EOF {
+				state.mu.RLock()
+				targetID := state.Config.ID
+				state.mu.RUnlock()
+				state.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "driver", Err: fmt.Sprintf("%s drain: %v", stream, err)})
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package target
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"opsnotebook/backend/internal/logging"
+)
+
+// ExecFrame is one line of the NDJSON stream the driver's
+// /execute/stream endpoint emits: either an output chunk (Stream/Data set)
+// or, as the final frame, the command's exit status (Exit set).
+type ExecFrame struct {
+	Stream     string    `json:"stream,omitempty"` // "stdout" or "stderr"
+	Data       string    `json:"data,omitempty"`
+	Timestamp  time.Time `json:"ts,omitempty"`
+	Exit       *int      `json:"exit,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Err        string    `json:"err,omitempty"`
+}
+
+// execStreamBufferSize bounds how many frames can be buffered between the
+// reader goroutine and a slow consumer before the reader blocks on send;
+// unlike Hub.publish this is a single-consumer stream so blocking (rather
+// than dropping) is correct here.
+const execStreamBufferSize = 32
+
+// DoExecStream behaves like DoExec but for long-running commands: instead
+// of buffering the whole response, it streams NDJSON frames from the
+// driver's /execute/stream endpoint as they arrive, so output shows up
+// incrementally instead of only after the command exits (or the request
+// times out). The returned channel is closed when the driver closes the
+// stream, ctx is canceled, or a read error occurs; the caller should drain
+// it to completion to release the underlying HTTP response body.
+func (s *State) DoExecStream(ctx context.Context, command string) (<-chan ExecFrame, error) {
+	s.mu.RLock()
+	controlURL := s.Connection.ControlURL
+	targetID := s.Config.ID
+	client := s.controlClient
+	s.mu.RUnlock()
+
+	if controlURL == "" {
+		return nil, fmt.Errorf("driver control url not available")
+	}
+
+	payload, _ := json.Marshal(map[string]string{"command": command})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, controlURL+"/execute/stream", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("driver returned status %d", resp.StatusCode)
+	}
+
+	frames := make(chan ExecFrame, execStreamBufferSize)
+	go func() {
+		defer close(frames)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			var frame ExecFrame
+			if err := json.Unmarshal(line, &frame); err != nil {
+				s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec-stream", Command: command, Err: fmt.Sprintf("malformed frame: %v", err)})
+				continue
+			}
+			select {
+			case frames <- frame:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec-stream", Command: command, Err: fmt.Sprintf("stream read error: %v", err)})
+		}
+	}()
+
+	return frames, nil
+}
+
+// StreamExec looks up targetID and streams command's output through it. See
+// State.DoExecStream.
+func (m *Manager) StreamExec(ctx context.Context, targetID, command string) (<-chan ExecFrame, error) {
+	state, ok := m.GetTarget(targetID)
+	if !ok {
+		return nil, fmt.Errorf("target not found: %s", targetID)
+	}
+	return state.DoExecStream(ctx, command)
+}
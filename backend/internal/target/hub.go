@@ -0,0 +1,137 @@
+package target
+
+import (
+	"sync"
+	"time"
+
+	"opsnotebook/backend/internal/config"
+)
+
+// EventKind identifies the category of a pub/sub event emitted by the Hub.
+type EventKind string
+
+const (
+	EventStatus  EventKind = "status"
+	EventLog     EventKind = "log"
+	EventRequest EventKind = "request"
+)
+
+// Event is a single message broadcast to Hub subscribers. Exactly one of
+// Status, Log, or Request is populated, matching Kind.
+type Event struct {
+	Kind      EventKind     `json:"kind"`
+	TargetID  string        `json:"target_id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Status    *StatusEvent  `json:"status,omitempty"`
+	Log       *LogEvent     `json:"log,omitempty"`
+	Request   *RequestEvent `json:"request,omitempty"`
+}
+
+// StatusEvent describes a target status transition, e.g. "connecting" -> "connected".
+type StatusEvent struct {
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Error string `json:"error,omitempty"`
+}
+
+// LogEvent carries a single line of driver stdout/stderr.
+type LogEvent struct {
+	Stream string `json:"stream"` // "stdout" or "stderr"
+	Line   string `json:"line"`
+}
+
+// RequestEvent summarizes a completed exec or proxy call against a target.
+type RequestEvent struct {
+	Kind       string `json:"kind"` // "exec" or "proxy"
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Command    string `json:"command,omitempty"`
+	Status     int    `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Err        string `json:"err,omitempty"`
+}
+
+// subscriber is one registered listener on the Hub.
+type subscriber struct {
+	ch       chan Event
+	targetID string // "" matches every target
+	tagGlob  string // "" skips tag filtering
+}
+
+// subscriberBufferSize bounds how many events a slow subscriber can lag
+// behind before events are dropped for it; publish never blocks.
+const subscriberBufferSize = 64
+
+// Hub is a small pub/sub broadcaster for target events. Subscribers can
+// filter by target ID or by a glob matched against the tag-derived group
+// key (the same composite key used for config pattern filtering and
+// grouping), so a client can tail e.g. "staging:*" without the API server
+// polling GetAllTargets.
+type Hub struct {
+	mu      sync.RWMutex
+	subs    map[int]*subscriber
+	nextID  int
+	groupBy []string
+}
+
+func newHub(groupBy []string) *Hub {
+	return &Hub{
+		subs:    make(map[int]*subscriber),
+		groupBy: groupBy,
+	}
+}
+
+// Subscribe registers a listener and returns its event channel along with an
+// unsubscribe function. An empty targetID or tagGlob disables that filter.
+func (h *Hub) Subscribe(targetID, tagGlob string) (<-chan Event, func()) {
+	h.mu.Lock()
+	id := h.nextID
+	h.nextID++
+	sub := &subscriber{
+		ch:       make(chan Event, subscriberBufferSize),
+		targetID: targetID,
+		tagGlob:  tagGlob,
+	}
+	h.subs[id] = sub
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		delete(h.subs, id)
+		h.mu.Unlock()
+		close(sub.ch)
+	}
+	return sub.ch, unsubscribe
+}
+
+// publish fans an event out to every subscriber whose filters match tags.
+// Sends never block the publisher. If a subscriber's buffer is full, the
+// oldest queued event is dropped to make room rather than dropping the new
+// one outright, so a lagging subscriber still sees recent activity instead
+// of getting stuck replaying a stale backlog.
+func (h *Hub) publish(ev Event, tags map[string]string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	groupKey := config.GroupKey(tags, h.groupBy)
+	for _, sub := range h.subs {
+		if sub.targetID != "" && sub.targetID != ev.TargetID {
+			continue
+		}
+		if sub.tagGlob != "" && !config.MatchGlob(sub.tagGlob, groupKey) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			select {
+			case <-sub.ch:
+			default:
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+			}
+		}
+	}
+}
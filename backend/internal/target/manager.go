@@ -1,29 +1,50 @@
 package target
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
 
 	"opsnotebook/backend/internal/config"
+	"opsnotebook/backend/internal/logging"
+	"opsnotebook/backend/internal/metrics"
 )
 
+// reloadWorkers bounds how many targets Reload tears down/starts/restarts
+// concurrently, so a config change touching hundreds of targets doesn't
+// fork hundreds of driver processes at once.
+const reloadWorkers = 8
+
+// getTag returns tags[key], or "unknown" if tags is nil or the key is absent.
+func getTag(tags map[string]string, key string) string {
+	if tags == nil {
+		return "unknown"
+	}
+	if v, ok := tags[key]; ok {
+		return v
+	}
+	return "unknown"
+}
+
 // ConnectionInfo represents the handshake data returned by the connector script
 type ConnectionInfo struct {
-	ControlURL string                 `json:"control_url"` // Internal field
-	URL        string                 `json:"target_url"`
-	Headers    map[string]string      `json:"headers"`
-	Metadata   map[string]interface{} `json:"metadata"`
+	ControlURL   string                 `json:"control_url"` // Internal field
+	URL          string                 `json:"target_url"`
+	Headers      map[string]string      `json:"headers"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	Capabilities []string               `json:"capabilities,omitempty"`
 }
 
 // State represents the runtime state of a target connection
@@ -35,10 +56,19 @@ type State struct {
 	LastChecked time.Time           `json:"last_checked,omitempty"`
 
 	// Private fields
-	cmd        *exec.Cmd
-	cancel     context.CancelFunc
-	controlURL string
-	mu         sync.RWMutex
+	cmd       *exec.Cmd
+	cancel    context.CancelFunc
+	hub       *Hub
+	logger    logging.Logger
+	driverLog *driverLogBuffer
+	mu        sync.RWMutex
+
+	// controlClient talks to the driver's control plane (/status, /connect,
+	// /execute, /execute/stream); it dials a Unix socket instead of TCP when
+	// the target's ControlTransport is "unix". controlSocketDir is the temp
+	// dir the socket lives in, removed on Disconnect.
+	controlClient    *http.Client
+	controlSocketDir string
 }
 
 func (s *State) Mu() *sync.RWMutex {
@@ -50,28 +80,61 @@ type Manager struct {
 	targets map[string]*State
 	rules   []config.VariableRule
 	groupBy []string
+	hub     *Hub
+	logger  logging.Logger
 	mu      sync.RWMutex
 	ctx     context.Context
 	cancel  context.CancelFunc
 }
 
-// NewManager constructs a Manager for cfg's targets.
-func NewManager(cfg *config.Config) *Manager {
+// NewManager constructs a Manager for cfg's targets. logger receives every
+// connect/disconnect/proxy/exec event Manager and its States log; pass nil
+// to fall back to logging.ConsoleSink{}, the pre-existing log.Printf-to-
+// stdout behavior.
+func NewManager(cfg *config.Config, logger logging.Logger) *Manager {
+	if logger == nil {
+		logger = logging.ConsoleSink{}
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &Manager{
 		targets: make(map[string]*State),
 		rules:   cfg.Variables,
 		groupBy: cfg.GroupBy,
+		hub:     newHub(cfg.GroupBy),
+		logger:  logger,
 		ctx:     ctx,
 		cancel:  cancel,
 	}
 
 	for _, t := range cfg.Targets {
-		m.targets[t.ID] = &State{Config: t, Status: StatusDisconnected}
+		m.targets[t.ID] = &State{
+			Config:    t,
+			Status:    StatusDisconnected,
+			hub:       m.hub,
+			logger:    m.logger,
+			driverLog: newDriverLogBuffer(),
+		}
 	}
 	return m
 }
 
+// publishStatus emits a StatusEvent on the hub for a target status
+// transition. Callers hold no lock on state when calling this.
+func (m *Manager) publishStatus(state *State, old, new, errStr string) {
+	m.hub.publish(Event{
+		Kind:      EventStatus,
+		TargetID:  state.Config.ID,
+		Timestamp: time.Now(),
+		Status:    &StatusEvent{Old: old, New: new, Error: errStr},
+	}, state.Config.Tags)
+}
+
+// Subscribe registers a listener on the manager's event hub. See Hub.Subscribe.
+// Pass "" for both targetID and tagGlob to receive every event unfiltered.
+func (m *Manager) Subscribe(targetID, tagGlob string) (<-chan Event, func()) {
+	return m.hub.Subscribe(targetID, tagGlob)
+}
+
 func (m *Manager) Shutdown() {
 	// Disconnect first to terminate driver processes before the manager
 	// context cancels.
@@ -104,56 +167,128 @@ func (m *Manager) Connect(state *State) {
 		state.mu.Unlock()
 		return
 	}
+	oldStatus := state.Status
 	state.Status = StatusConnecting
 	state.Error = ""
 	state.mu.Unlock()
+	m.publishStatus(state, oldStatus, StatusConnecting, "")
 
 	cfg := state.Config
-	log.Printf("[target] Connecting %s...", cfg.ID)
+	m.logger.Log(logging.Record{Time: time.Now(), TargetID: cfg.ID, Message: "Connecting..."})
+	metrics.ReconnectAttempts.WithLabelValues(cfg.ID).Inc()
 
 	ctx, cancel := context.WithCancel(m.ctx)
 	connectCtx, connectCancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer connectCancel()
 
-	// 1. Allocate a loopback TCP port for the driver's control plane
-	l, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
+	// 1. Allocate the control plane transport: a Unix socket in a per-target
+	// temp dir by default, or a loopback TCP port if ControlTransport asks
+	// for "tcp" (the only option on platforms without defaultControlTransport
+	// == "unix").
+	transport := cfg.ControlTransport
+	if transport == "" {
+		transport = defaultControlTransport
+	}
+
+	var controlURL, socketPath, socketDir, controlEnv string
+	switch transport {
+	case "unix":
+		dir, err := os.MkdirTemp("", "opsnotebook-"+cfg.ID+"-")
+		if err != nil {
+			cancel()
+			m.setError(state, fmt.Sprintf("create control socket dir: %v", err))
+			return
+		}
+		socketDir = dir
+		socketPath = filepath.Join(dir, "control.sock")
+		controlURL = "http://unix"
+		controlEnv = fmt.Sprintf("OPSNOTEBOOK_CONTROL_SOCKET=%s", socketPath)
+	case "tcp":
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			cancel()
+			m.setError(state, fmt.Sprintf("find port: %v", err))
+			return
+		}
+		controlPort := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+		controlURL = fmt.Sprintf("http://127.0.0.1:%d", controlPort)
+		controlEnv = fmt.Sprintf("OPSNOTEBOOK_CONTROL_PORT=%d", controlPort)
+	default:
 		cancel()
-		m.setError(state, fmt.Sprintf("find port: %v", err))
+		m.setError(state, fmt.Sprintf("unknown control_transport %q", transport))
 		return
 	}
-	controlPort := l.Addr().(*net.TCPAddr).Port
-	l.Close()
-	controlURL := fmt.Sprintf("http://127.0.0.1:%d", controlPort)
+	client := newControlClient(transport, socketPath)
 
-	// 2. Start Driver Process
+	// 2. Start Driver Process, with stdout/stderr captured into the
+	// per-target driver log instead of inherited, so a chatty driver (or an
+	// exec'd grandchild that inherits its pipes) can't deadlock on a full
+	// pipe buffer the way it would with cmd.Stderr = os.Stderr and stdout
+	// left unset.
 	cmd := exec.CommandContext(ctx, "bash", "-c", cfg.DriverCmd)
-	cmd.Env = append(os.Environ(), fmt.Sprintf("OPSNOTEBOOK_CONTROL_PORT=%d", controlPort))
-	cmd.Stderr = os.Stderr
+	configureDriverCmd(cmd)
+	cmd.Env = append(os.Environ(), controlEnv)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		if socketDir != "" {
+			os.RemoveAll(socketDir)
+		}
+		m.setError(state, fmt.Sprintf("stdout pipe: %v", err))
+		return
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		if socketDir != "" {
+			os.RemoveAll(socketDir)
+		}
+		m.setError(state, fmt.Sprintf("stderr pipe: %v", err))
+		return
+	}
 
 	if err := cmd.Start(); err != nil {
 		cancel()
+		if socketDir != "" {
+			os.RemoveAll(socketDir)
+		}
 		m.setError(state, fmt.Sprintf("start cmd: %v", err))
 		return
 	}
 
+	var ioWG sync.WaitGroup
+	ioWG.Add(2)
+	go func() { defer ioWG.Done(); m.drainDriverOutput(state, "stdout", stdout) }()
+	go func() { defer ioWG.Done(); m.drainDriverOutput(state, "stderr", stderr) }()
+
 	state.mu.Lock()
 	state.cmd = cmd
 	state.cancel = cancel
-	state.controlURL = controlURL
+	state.controlClient = client
+	state.controlSocketDir = socketDir
 	state.mu.Unlock()
 
 	// 3. Wait for Driver HTTP Server (poll /status)
-	if !waitForDriver(connectCtx, controlURL) {
+	if !m.waitForDriver(connectCtx, client, controlURL) {
 		cancel()
+		go func() { ioWG.Wait(); _ = cmd.Wait() }()
+		if socketDir != "" {
+			os.RemoveAll(socketDir)
+		}
 		m.setError(state, "driver failed to start http server")
 		return
 	}
 
 	// 4. Send Connect Request
-	connInfo, err := sendConnect(connectCtx, controlURL)
+	connInfo, err := m.sendConnect(connectCtx, client, controlURL)
 	if err != nil {
 		cancel()
+		go func() { ioWG.Wait(); _ = cmd.Wait() }()
+		if socketDir != "" {
+			os.RemoveAll(socketDir)
+		}
 		m.setError(state, fmt.Sprintf("connect failed: %v", err))
 		return
 	}
@@ -164,10 +299,17 @@ func (m *Manager) Connect(state *State) {
 	state.Status = StatusConnected
 	state.LastChecked = time.Now()
 	state.mu.Unlock()
+	m.publishStatus(state, StatusConnecting, StatusConnected, "")
+	metrics.SetTargetUp(cfg.ID, cfg.Name, getTag(cfg.Tags, "environment"), getTag(cfg.Tags, "region"), getTag(cfg.Tags, "variant"), true)
+	metrics.ActiveConnections.Inc()
 
-	log.Printf("[target] Connected %s: %s", cfg.ID, connInfo.URL)
+	m.logger.Log(logging.Record{Time: time.Now(), TargetID: cfg.ID, Event: "connect", Message: fmt.Sprintf("Connected: %s (Control: %s)", connInfo.URL, controlURL)})
 
 	go func() {
+		// Wait for both drain goroutines to see EOF before calling cmd.Wait:
+		// Wait closes the stdout/stderr pipes once the process exits, and
+		// reading from an already-closed pipe is undefined.
+		ioWG.Wait()
 		_ = cmd.Wait()
 		if ctx.Err() != nil || m.ctx.Err() != nil {
 			return
@@ -175,14 +317,23 @@ func (m *Manager) Connect(state *State) {
 
 		state.mu.Lock()
 		wasConnected := state.Status == StatusConnected
+		dir := state.controlSocketDir
 		if wasConnected {
 			state.Status = StatusDisconnected
 			state.Connection = ConnectionInfo{}
-			log.Printf("[target] %s: process exited unexpectedly", cfg.ID)
+			state.controlClient = nil
+			state.controlSocketDir = ""
+			m.logger.Log(logging.Record{Time: time.Now(), TargetID: cfg.ID, Event: "disconnect", Message: "Process exited unexpectedly"})
 		}
 		state.mu.Unlock()
 
 		if wasConnected {
+			if dir != "" {
+				os.RemoveAll(dir)
+			}
+			m.publishStatus(state, StatusConnected, StatusDisconnected, "")
+			metrics.SetTargetUp(cfg.ID, cfg.Name, getTag(cfg.Tags, "environment"), getTag(cfg.Tags, "region"), getTag(cfg.Tags, "variant"), false)
+			metrics.ActiveConnections.Dec()
 			// Auto-reconnect only for unexpected exits.
 			time.Sleep(5 * time.Second)
 			m.Connect(state)
@@ -190,13 +341,14 @@ func (m *Manager) Connect(state *State) {
 	}()
 }
 
-func waitForDriver(ctx context.Context, url string) bool {
+func (m *Manager) waitForDriver(ctx context.Context, client *http.Client, url string) bool {
 	for i := 0; i < 50; i++ { // 5 seconds timeout
 		reqCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
 		req, _ := http.NewRequestWithContext(reqCtx, "GET", url+"/status", nil)
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := client.Do(req)
 		cancel()
 		if err == nil && resp.StatusCode == 200 {
+			m.logger.Log(logging.Record{Time: time.Now(), Event: "driver", Method: "GET", Path: url + "/status", Status: resp.StatusCode})
 			resp.Body.Close()
 			return true
 		}
@@ -205,17 +357,20 @@ func waitForDriver(ctx context.Context, url string) bool {
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
+	m.logger.Log(logging.Record{Time: time.Now(), Event: "driver", Method: "GET", Path: url + "/status", Err: "timeout after 5s"})
 	return false
 }
 
-func sendConnect(ctx context.Context, url string) (*ConnectionInfo, error) {
+func (m *Manager) sendConnect(ctx context.Context, client *http.Client, url string) (*ConnectionInfo, error) {
 	req, _ := http.NewRequestWithContext(ctx, "POST", url+"/connect", nil)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
+		m.logger.Log(logging.Record{Time: time.Now(), Event: "driver", Method: "POST", Path: url + "/connect", Err: err.Error()})
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	m.logger.Log(logging.Record{Time: time.Now(), Event: "driver", Method: "POST", Path: url + "/connect", Status: resp.StatusCode})
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
@@ -233,21 +388,33 @@ func (m *Manager) Disconnect(state *State) {
 	cmd := state.cmd
 	cancel := state.cancel
 	oldStatus := state.Status
+	socketDir := state.controlSocketDir
+	cfg := state.Config
 	state.cmd = nil
 	state.cancel = nil
-	state.controlURL = ""
+	state.controlClient = nil
+	state.controlSocketDir = ""
 	state.Status = StatusDisconnected
 	state.Connection = ConnectionInfo{}
 	state.mu.Unlock()
 
-	if cmd != nil && cmd.Process != nil {
-		_ = cmd.Process.Kill()
+	if socketDir != "" {
+		os.RemoveAll(socketDir)
+	}
+
+	if cmd != nil {
+		terminateDriverCmd(cmd, gracePeriod(cfg))
 	}
 	if cancel != nil {
 		cancel()
 	}
 	if oldStatus != StatusDisconnected {
-		log.Printf("[target] Disconnected %s", state.Config.ID)
+		m.logger.Log(logging.Record{Time: time.Now(), TargetID: state.Config.ID, Event: "disconnect", Message: "Disconnected"})
+		m.publishStatus(state, oldStatus, StatusDisconnected, "")
+		if oldStatus == StatusConnected {
+			metrics.SetTargetUp(state.Config.ID, state.Config.Name, getTag(state.Config.Tags, "environment"), getTag(state.Config.Tags, "region"), getTag(state.Config.Tags, "variant"), false)
+			metrics.ActiveConnections.Dec()
+		}
 	}
 }
 
@@ -259,12 +426,113 @@ func (m *Manager) DisconnectAll() {
 	}
 }
 
+// Reload reconciles the running target set against cfg: targets no longer
+// present are torn down, new targets are connected, and targets whose
+// DriverCmd/Tags/Variables changed are disconnected and reconnected with
+// their new config. Targets that are unchanged keep their existing
+// connection untouched. The swap of m.targets happens under m.mu so
+// GetAllTargets/GetTarget always see a consistent snapshot; the teardown/
+// start/restart work itself runs in a bounded pool of goroutines so it
+// never blocks readers and a large config doesn't fork every driver at once.
+func (m *Manager) Reload(cfg *config.Config) {
+	desired := make(map[string]config.TargetConfig, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		desired[t.ID] = t
+	}
+
+	m.mu.Lock()
+	m.rules = cfg.Variables
+	m.groupBy = cfg.GroupBy
+
+	var removed, added, changed []*State
+	newTargets := make(map[string]*State, len(desired))
+
+	for id, t := range desired {
+		if st, ok := m.targets[id]; ok {
+			st.mu.Lock()
+			restart := targetChanged(st.Config, t)
+			st.Config = t
+			st.mu.Unlock()
+			if restart {
+				changed = append(changed, st)
+			}
+			newTargets[id] = st
+		} else {
+			ns := &State{Config: t, Status: StatusDisconnected, hub: m.hub, logger: m.logger, driverLog: newDriverLogBuffer()}
+			newTargets[id] = ns
+			added = append(added, ns)
+		}
+	}
+	for id, st := range m.targets {
+		if _, ok := desired[id]; !ok {
+			removed = append(removed, st)
+		}
+	}
+	m.targets = newTargets
+	m.mu.Unlock()
+
+	m.logger.Log(logging.Record{Time: time.Now(), Event: "reload", Message: fmt.Sprintf("Reload: %d added, %d changed, %d removed", len(added), len(changed), len(removed))})
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, reloadWorkers)
+	run := func(state *State, fn func(*State)) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(state)
+		}()
+	}
+
+	for _, st := range removed {
+		run(st, m.Disconnect)
+	}
+	for _, st := range changed {
+		run(st, func(st *State) {
+			m.Disconnect(st)
+			m.Connect(st)
+		})
+	}
+	for _, st := range added {
+		run(st, m.Connect)
+	}
+	wg.Wait()
+}
+
+// gracePeriod returns how long terminateDriverCmd should wait after SIGTERM
+// before escalating to SIGKILL for cfg, falling back to DefaultGracePeriod
+// when cfg doesn't set GracePeriodSeconds.
+func gracePeriod(cfg config.TargetConfig) time.Duration {
+	if cfg.GracePeriodSeconds <= 0 {
+		return DefaultGracePeriod * time.Second
+	}
+	return time.Duration(cfg.GracePeriodSeconds) * time.Second
+}
+
+// targetChanged reports whether old -> new requires tearing down and
+// reconnecting a target's driver process, as opposed to a metadata-only
+// change (e.g. Labels, Visual) that the running connection is unaffected by.
+func targetChanged(old, new config.TargetConfig) bool {
+	return old.DriverCmd != new.DriverCmd ||
+		!reflect.DeepEqual(old.Tags, new.Tags) ||
+		!reflect.DeepEqual(old.Variables, new.Variables)
+}
+
 func (m *Manager) setError(state *State, err string) {
 	state.mu.Lock()
+	oldStatus := state.Status
+	cfg := state.Config
 	state.Status = StatusError
 	state.Error = err
 	state.mu.Unlock()
-	log.Printf("[target] %s error: %s", state.Config.ID, err)
+	m.publishStatus(state, oldStatus, StatusError, err)
+	metrics.ReconnectFailures.WithLabelValues(cfg.ID).Inc()
+	if oldStatus == StatusConnected {
+		metrics.SetTargetUp(cfg.ID, cfg.Name, getTag(cfg.Tags, "environment"), getTag(cfg.Tags, "region"), getTag(cfg.Tags, "variant"), false)
+		metrics.ActiveConnections.Dec()
+	}
+	m.logger.Log(logging.Record{Time: time.Now(), TargetID: state.Config.ID, Event: "error", Err: err})
 
 	go func() {
 		time.Sleep(30 * time.Second)
@@ -318,18 +586,58 @@ func (m *Manager) ResolveVariables(cfg config.TargetConfig) map[string]interface
 	return result
 }
 
-// DoProxyRequest forwards a request to the target's driver.
-func (s *State) DoProxyRequest(method, path string, body []byte) (json.RawMessage, int, error) {
+// ArmRequestDeadline returns a context derived from parent that is canceled
+// after d (or never, if d <= 0), for use around a single exec/proxy call.
+// It allocates its own timer per call rather than sharing one on State: a
+// target routinely serves concurrent exec/proxy calls (multiple browser
+// tabs, overlapping exec+proxy), and a shared timer would have one call's
+// arm silently stop another's still-in-flight deadline, unlike net.Conn
+// (one read/write in flight) which this was originally modeled on. If the
+// deadline fires, it also kills the driver process: a call that's still
+// blocked at the deadline means the driver itself is hung, not just slow.
+func (s *State) ArmRequestDeadline(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancelCtx := context.WithCancel(parent)
+	if d <= 0 {
+		return ctx, cancelCtx
+	}
+
+	onExpire := func() {
+		cancelCtx()
+		s.mu.RLock()
+		cmd := s.cmd
+		cfg := s.Config
+		s.mu.RUnlock()
+		if cmd != nil {
+			s.logger.Log(logging.Record{Time: time.Now(), TargetID: cfg.ID, Message: "Request deadline exceeded, terminating driver"})
+			terminateDriverCmd(cmd, gracePeriod(cfg))
+		}
+	}
+
+	timer := time.AfterFunc(d, onExpire)
+
+	return ctx, func() {
+		timer.Stop()
+		cancelCtx()
+	}
+}
+
+// DoProxyRequest forwards a request to the target's driver. extraHeaders is
+// applied on top of the target's own Connection.Headers (e.g. from a
+// TrafficController), so it can add or override individual headers.
+func (s *State) DoProxyRequest(ctx context.Context, method, path string, body []byte, extraHeaders map[string]string) (json.RawMessage, int, error) {
 	s.mu.RLock()
 	status := s.Status
 	baseURL := s.Connection.URL
 	headers := s.Connection.Headers
 	s.mu.RUnlock()
 
+	targetID := s.Config.ID
 	if status != StatusConnected {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Err: fmt.Sprintf("target not connected (status: %s)", status)})
 		return nil, 0, fmt.Errorf("target not connected (status: %s)", status)
 	}
 	if baseURL == "" {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Err: "target has no base URL"})
 		return nil, 0, fmt.Errorf("target has no base URL")
 	}
 
@@ -345,8 +653,9 @@ func (s *State) DoProxyRequest(method, path string, body []byte) (json.RawMessag
 		bodyReader = strings.NewReader(string(body))
 	}
 
-	req, err := http.NewRequest(method, fullURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Err: err.Error()})
 		return nil, 0, err
 	}
 	req.Header.Set("Accept", "application/json")
@@ -356,46 +665,87 @@ func (s *State) DoProxyRequest(method, path string, body []byte) (json.RawMessag
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range extraHeaders {
+		req.Header.Set(k, v)
+	}
 
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Err: err.Error(), DurationMS: time.Since(start).Milliseconds()})
 		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
+	duration := time.Since(start)
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Status: resp.StatusCode, Err: fmt.Sprintf("read body: %v", err), DurationMS: duration.Milliseconds()})
 		return nil, resp.StatusCode, fmt.Errorf("read body: %w", err)
 	}
+	s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "proxy", Method: method, Path: path, Status: resp.StatusCode, DurationMS: duration.Milliseconds()})
+	s.publishRequest(RequestEvent{Kind: "proxy", Method: method, Path: path, Status: resp.StatusCode, DurationMS: duration.Milliseconds()})
+	metrics.ObserveProxy(s.Config.ID, method, duration)
 	return respBody, resp.StatusCode, nil
 }
 
-// DoExec dispatches command to the target's driver control plane.
-func (s *State) DoExec(command string) (json.RawMessage, int, error) {
+// DoExec dispatches command to the target's driver control plane. ctx
+// governs the call's deadline/cancellation; see ArmRequestDeadline.
+func (s *State) DoExec(ctx context.Context, command string) (json.RawMessage, int, error) {
 	s.mu.RLock()
-	controlURL := s.controlURL
+	controlURL := s.Connection.ControlURL
+	targetID := s.Config.ID
+	client := s.controlClient
 	s.mu.RUnlock()
 
 	if controlURL == "" {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec", Command: command, Err: "driver control url not available"})
 		return nil, 0, fmt.Errorf("driver control url not available")
 	}
 
 	payload, _ := json.Marshal(map[string]string{"command": command})
-	req, err := http.NewRequest(http.MethodPost, controlURL+"/execute", strings.NewReader(string(payload)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, controlURL+"/execute", bytes.NewReader(payload))
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec", Command: command, Err: err.Error()})
 		return nil, 0, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	start := time.Now()
+	resp, err := client.Do(req)
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec", Command: command, Err: err.Error(), DurationMS: time.Since(start).Milliseconds()})
 		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
+	duration := time.Since(start)
 	if err != nil {
+		s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec", Command: command, Status: resp.StatusCode, Err: fmt.Sprintf("read body: %v", err), DurationMS: duration.Milliseconds()})
 		return nil, resp.StatusCode, err
 	}
-	return body, resp.StatusCode, nil
+	s.logger.Log(logging.Record{Time: time.Now(), TargetID: targetID, Event: "exec", Command: command, Status: resp.StatusCode, DurationMS: duration.Milliseconds()})
+	s.publishRequest(RequestEvent{Kind: "exec", Command: command, Status: resp.StatusCode, DurationMS: duration.Milliseconds()})
+	metrics.ObserveExec(s.Config.ID, duration)
+	return respBody, resp.StatusCode, nil
+}
+
+// publishRequest emits a RequestEvent summarizing a completed exec or proxy
+// call so subscribers can tail per-target activity without polling.
+func (s *State) publishRequest(req RequestEvent) {
+	if s.hub == nil {
+		return
+	}
+	s.mu.RLock()
+	targetID := s.Config.ID
+	tags := s.Config.Tags
+	s.mu.RUnlock()
+
+	s.hub.publish(Event{
+		Kind:      EventRequest,
+		TargetID:  targetID,
+		Timestamp: time.Now(),
+		Request:   &req,
+	}, tags)
 }
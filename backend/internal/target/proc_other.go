@@ -0,0 +1,25 @@
+//go:build !darwin && !linux
+
+package target
+
+import (
+	"os/exec"
+	"time"
+)
+
+// defaultControlTransport: Unix domain sockets aren't assumed available on
+// non-POSIX platforms, so fall back to a loopback TCP port.
+const defaultControlTransport = "tcp"
+
+func configureDriverCmd(cmd *exec.Cmd) {}
+
+// terminateDriverCmd kills cmd directly; grace is unused here since there's
+// no process-group signal to wait out.
+// TODO: If Windows support becomes important, prefer a Job Object so child
+// processes don't outlive the backend, and honor grace with SIGTERM-then-kill.
+func terminateDriverCmd(cmd *exec.Cmd, grace time.Duration) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,52 @@
+//go:build darwin || linux
+
+package target
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// defaultControlTransport is used when a TargetConfig doesn't set
+// ControlTransport explicitly. Unix domain sockets are filesystem-
+// permission-scoped and avoid the ephemeral-port exhaustion and
+// listen/close races of allocating a TCP port per target.
+const defaultControlTransport = "unix"
+
+func configureDriverCmd(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateDriverCmdPollInterval is how often terminateDriverCmd checks
+// whether the process group has exited while waiting out grace.
+const terminateDriverCmdPollInterval = 100 * time.Millisecond
+
+// terminateDriverCmd sends SIGTERM to cmd's whole process group (to avoid
+// leaking child processes created by shell wrappers like `bash -c ...` or by
+// the driver itself), then escalates to SIGKILL if it hasn't exited within
+// grace. It polls for exit with Kill(pid, 0) (checks the group leader still
+// exists, sends nothing) rather than sleeping blindly for grace and firing
+// SIGKILL unconditionally: a blind sleep-then-kill can hit a pid the OS has
+// since recycled for an unrelated process once the group leader has exited.
+func terminateDriverCmd(cmd *exec.Cmd, grace time.Duration) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	pid := cmd.Process.Pid
+
+	_ = syscall.Kill(-pid, syscall.SIGTERM)
+
+	go func() {
+		deadline := time.Now().Add(grace)
+		for time.Now().Before(deadline) {
+			time.Sleep(terminateDriverCmdPollInterval)
+			if err := syscall.Kill(-pid, 0); err != nil {
+				// ESRCH (or similar): the group leader is gone, nothing left
+				// to escalate to SIGKILL.
+				return
+			}
+		}
+		_ = syscall.Kill(-pid, syscall.SIGKILL)
+	}()
+}
@@ -0,0 +1,160 @@
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"opsnotebook/backend/internal/config"
+)
+
+type confirmationTokenKey struct{}
+
+// WithConfirmationToken returns a context carrying the caller-supplied
+// confirmation token (the API layer reads it from the X-Confirm-Token
+// header) for RuleController to check against require_confirmation_token
+// rules.
+func WithConfirmationToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, confirmationTokenKey{}, token)
+}
+
+func confirmationTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(confirmationTokenKey{}).(string)
+	return token
+}
+
+// RuleController evaluates an ordered list of config.TrafficRule against
+// each call, applying the first matching rule's action. It implements the
+// "deny | rewrite_path | inject_header | require_confirmation_token |
+// audit" vocabulary from config.json's traffic_rules block. audit, if
+// non-nil, is used to log calls matching a rule with Then.Audit set, on top
+// of whatever AuditController already does elsewhere in the chain.
+type RuleController struct {
+	rules []config.TrafficRule
+	audit *AuditController
+}
+
+// NewRuleController builds a RuleController from rules. audit may be nil if
+// no traffic_audit_log is configured; rules with Then.Audit set then have no
+// effect.
+func NewRuleController(rules []config.TrafficRule, audit *AuditController) *RuleController {
+	return &RuleController{rules: rules, audit: audit}
+}
+
+func (r *RuleController) matchRule(tags map[string]string, method, path string) *config.TrafficRule {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.When.Method != "" && rule.When.Method != method {
+			continue
+		}
+		if rule.When.PathGlob != "" && !config.MatchGlob(rule.When.PathGlob, path) {
+			continue
+		}
+		match := true
+		for k, v := range rule.When.Tags {
+			if tags[k] != v {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+func (r *RuleController) InterceptExec(ctx context.Context, state *State, command string) (string, error) {
+	state.mu.RLock()
+	tags := state.Config.Tags
+	state.mu.RUnlock()
+
+	rule := r.matchRule(tags, "exec", "exec")
+	if rule == nil {
+		return command, nil
+	}
+	if rule.Then.Deny {
+		return command, &DenyError{Reason: "exec denied by traffic rule"}
+	}
+	if rule.Then.RequireConfirmationToken && confirmationTokenFromContext(ctx) == "" {
+		return command, &DenyError{Reason: "exec requires confirmation token"}
+	}
+	return command, nil
+}
+
+func (r *RuleController) InterceptProxy(ctx context.Context, state *State, method, path string, body []byte, headers map[string]string) (string, string, []byte, map[string]string, error) {
+	state.mu.RLock()
+	tags := state.Config.Tags
+	state.mu.RUnlock()
+
+	rule := r.matchRule(tags, method, path)
+	if rule == nil {
+		return method, path, body, headers, nil
+	}
+	if rule.Then.Deny {
+		return method, path, body, headers, &DenyError{Reason: "proxy call denied by traffic rule"}
+	}
+	if rule.Then.RequireConfirmationToken && confirmationTokenFromContext(ctx) == "" {
+		return method, path, body, headers, &DenyError{Reason: "proxy call requires confirmation token"}
+	}
+	if rule.Then.RewritePath != "" {
+		path = rule.Then.RewritePath
+	}
+	if len(rule.Then.InjectHeader) > 0 {
+		if headers == nil {
+			headers = make(map[string]string, len(rule.Then.InjectHeader))
+		}
+		for k, v := range rule.Then.InjectHeader {
+			headers[k] = v
+		}
+	}
+	return method, path, body, headers, nil
+}
+
+func (r *RuleController) AfterExec(ctx context.Context, state *State, command string, resp json.RawMessage, status int) {
+	if r.audit == nil {
+		return
+	}
+	state.mu.RLock()
+	tags := state.Config.Tags
+	targetID := state.Config.ID
+	state.mu.RUnlock()
+
+	rule := r.matchRule(tags, "exec", "exec")
+	if rule == nil || !rule.Then.Audit {
+		return
+	}
+	r.audit.write(auditRecord{
+		Timestamp: time.Now(),
+		TargetID:  targetID,
+		User:      r.audit.identity(ctx),
+		Kind:      "exec",
+		Command:   command,
+		Status:    status,
+	})
+}
+
+func (r *RuleController) AfterProxy(ctx context.Context, state *State, method, path string, resp json.RawMessage, status int) {
+	if r.audit == nil {
+		return
+	}
+	state.mu.RLock()
+	tags := state.Config.Tags
+	targetID := state.Config.ID
+	state.mu.RUnlock()
+
+	rule := r.matchRule(tags, method, path)
+	if rule == nil || !rule.Then.Audit {
+		return
+	}
+	r.audit.write(auditRecord{
+		Timestamp: time.Now(),
+		TargetID:  targetID,
+		User:      r.audit.identity(ctx),
+		Kind:      "proxy",
+		Method:    method,
+		Path:      path,
+		Status:    status,
+	})
+}